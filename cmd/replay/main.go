@@ -0,0 +1,48 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"modules/internal/collision"
+	"modules/internal/core"
+	"modules/internal/game"
+	"modules/internal/ioc"
+	"modules/internal/journal"
+)
+
+var (
+	journalFile string
+	cellSize    int
+)
+
+func init() {
+	flag.StringVar(&journalFile, "i", "", "journal file")
+	flag.IntVar(&cellSize, "cell-size", collision.DefaultCellSize, "collision cell size of the game")
+}
+
+func main() {
+	flag.Parse()
+
+	file, err := os.Open(journalFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer file.Close()
+
+	err = ioc.Resolve("IoC.Register", "Game.CollisionCellSize", func(params ...interface{}) interface{} {
+		return cellSize
+	}).(core.Command).Execute()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	result, err := journal.Replay(file, game.NewReplayCodec)
+	if result != nil {
+		log.Printf("replayed %d commands, verified %d checkpoints", result.Commands, result.Checkpoints)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,434 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"modules/internal/auth"
+	"modules/internal/clock"
+	"modules/internal/collision"
+	"modules/internal/config"
+	"modules/internal/core"
+	"modules/internal/game"
+	"modules/internal/grpcapi"
+	"modules/internal/grpcapi/pb"
+	"modules/internal/httpapi"
+	"modules/internal/interpreter"
+	"modules/internal/ioc"
+	"modules/internal/plugin"
+	"modules/internal/repository"
+	"modules/internal/snapshot"
+	"modules/internal/spectator"
+)
+
+var (
+	settingsFile    string
+	iocConfigFile   string
+	httpAddress     string
+	grpcAddress     string
+	bufferLength    int
+	shutdownTimeout time.Duration
+)
+
+func init() {
+	flag.StringVar(&settingsFile, "c", "", "server config file")
+	flag.StringVar(&iocConfigFile, "ioc", "", "ioc config file")
+	flag.StringVar(&httpAddress, "http", "", "HTTP API address")
+	flag.StringVar(&grpcAddress, "grpc", "", "gRPC API address")
+	flag.IntVar(&bufferLength, "buffer", 0, "game queue length")
+	flag.DurationVar(&shutdownTimeout, "shutdown-timeout", 0, "time given to games to finish on shutdown")
+}
+
+func main() {
+	flag.Parse()
+
+	s, err := loadSettings(settingsFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	applyFlags(&s)
+
+	err = setupIoC(s)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	manager := game.NewManager(s.BufferLength)
+	handler := httpapi.NewHandler(manager, s.MaxBodySize, s.WaitTimeout)
+
+	var authService *auth.Service
+	if s.Auth != nil {
+		authService, err = auth.LoadService(s.Auth.PrivateKey, s.Auth.PublicKey, s.Auth.TokenTTL)
+		if err != nil {
+			log.Fatal(err)
+		}
+		authService.SetClock(ioc.Resolve("Clock").(clock.Clock))
+		manager.SetOrderWrapper(authService.Wrap)
+		handler.SetAuthService(authService)
+		handler.SetAdminKey(s.Auth.AdminKey)
+	}
+
+	games, _ := ioc.Resolve("Repository").(repository.Repository)
+	restored, err := restoreGames(manager, s.SnapshotDir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	err = loadGames(manager, games, restored)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if authService != nil {
+		err = registerPlayers(authService, manager, restored)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	for _, g := range s.Games {
+		if !restored[g.ID] {
+			objects, err := g.registry()
+			if err != nil {
+				log.Fatalf("game %q: %s", g.ID, err)
+			}
+
+			_, err = manager.Create(g.ID, objects)
+			if err != nil {
+				log.Fatal(err)
+			}
+			log.Printf("game %q started", g.ID)
+		}
+		if authService != nil {
+			authService.RegisterGame(g.ID, g.playerIDs())
+		}
+	}
+
+	hub := spectator.NewHub(manager)
+	mux := http.NewServeMux()
+	mux.Handle("/games", handler)
+	mux.Handle("/games/", handler)
+	mux.Handle("/watch/", http.StripPrefix("/watch", hub))
+
+	server := &http.Server{
+		Addr:    s.HTTPAddress,
+		Handler: mux,
+	}
+	go func() {
+		err := server.ListenAndServe()
+		if err != nil && err != http.ErrServerClosed {
+			log.Fatal(err)
+		}
+	}()
+	log.Printf("HTTP API listening on %s", s.HTTPAddress)
+
+	grpcListener, err := net.Listen("tcp", s.GRPCAddress)
+	if err != nil {
+		log.Fatal(err)
+	}
+	grpcServer := grpc.NewServer()
+	grpcGameServer := grpcapi.NewServer(manager)
+	if authService != nil {
+		grpcGameServer.SetAuthService(authService)
+	}
+	pb.RegisterGameServiceServer(grpcServer, grpcGameServer)
+	go func() {
+		err := grpcServer.Serve(grpcListener)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}()
+	log.Printf("gRPC API listening on %s", s.GRPCAddress)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+	<-ctx.Done()
+
+	log.Println("shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.ShutdownTimeout)
+	defer cancel()
+
+	err = server.Shutdown(shutdownCtx)
+	if err != nil {
+		log.Println(err)
+	}
+	grpcServer.Stop()
+
+	err = manager.Shutdown(shutdownCtx)
+	hub.Close()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if games != nil {
+		err = games.Close()
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+func applyFlags(s *settings) {
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "ioc":
+			s.IoCConfig = iocConfigFile
+		case "http":
+			s.HTTPAddress = httpAddress
+		case "grpc":
+			s.GRPCAddress = grpcAddress
+		case "buffer":
+			s.BufferLength = bufferLength
+		case "shutdown-timeout":
+			s.ShutdownTimeout = shutdownTimeout
+		}
+	})
+}
+
+func setupIoC(s settings) error {
+	err := interpreter.RegisterOperations()
+	if err != nil {
+		return err
+	}
+
+	err = collision.RegisterRules()
+	if err != nil {
+		return err
+	}
+
+	err = ioc.Resolve("IoC.Register", "Game.TickInterval", func(params ...interface{}) interface{} {
+		return s.TickInterval
+	}).(core.Command).Execute()
+	if err != nil {
+		return err
+	}
+
+	err = ioc.Resolve("IoC.Register", "Game.CollisionCellSize", func(params ...interface{}) interface{} {
+		return s.CellSize
+	}).(core.Command).Execute()
+	if err != nil {
+		return err
+	}
+
+	maps, err := s.maps()
+	if err != nil {
+		return err
+	}
+	err = ioc.Resolve("IoC.Register", "Maps", func(params ...interface{}) interface{} {
+		if result, ok := maps[params[0].(string)]; ok {
+			return result
+		}
+		if result, ok := maps[""]; ok {
+			return result
+		}
+		return nil
+	}).(core.Command).Execute()
+	if err != nil {
+		return err
+	}
+
+	err = registerJournals(s)
+	if err != nil {
+		return err
+	}
+
+	err = registerSnapshots(s)
+	if err != nil {
+		return err
+	}
+
+	games, err := s.Storage.repository()
+	if err != nil {
+		return err
+	}
+	if games != nil {
+		err = ioc.Resolve("IoC.Register", "Repository", func(params ...interface{}) interface{} {
+			return games
+		}).(core.Command).Execute()
+		if err != nil {
+			return err
+		}
+	}
+
+	realClock := clock.NewReal()
+	err = ioc.Resolve("IoC.Register", "Clock", func(params ...interface{}) interface{} {
+		return realClock
+	}).(core.Command).Execute()
+	if err != nil {
+		return err
+	}
+
+	err = plugin.LoadAll("")
+	if err != nil {
+		return err
+	}
+
+	if s.IoCConfig == "" {
+		return nil
+	}
+
+	cfg, err := config.Load(s.IoCConfig)
+	if err != nil {
+		return err
+	}
+
+	return cfg.Apply()
+}
+
+// registerJournals makes games journal their commands to
+// <id>.<start time>.jsonl files in the journal directory, if set. A game
+// started again, e.g. restored from a snapshot, gets a new journal, which
+// starts with the objects it has been restored with.
+func registerJournals(s settings) error {
+	err := ioc.Resolve("IoC.Register", "Game.JournalCheckpointInterval", func(params ...interface{}) interface{} {
+		return s.Checkpoints
+	}).(core.Command).Execute()
+	if err != nil {
+		return err
+	}
+
+	if s.JournalDir == "" {
+		return nil
+	}
+
+	err = os.MkdirAll(s.JournalDir, 0o755)
+	if err != nil {
+		return err
+	}
+
+	return ioc.Resolve("IoC.Register", "Journals", func(params ...interface{}) interface{} {
+		id := params[0].(string)
+		err := game.ValidateID(id)
+		if err != nil {
+			log.Printf("journal: %s", err)
+			return nil
+		}
+
+		name := id + "." + time.Now().UTC().Format("20060102T150405.000000000") + ".jsonl"
+		file, err := os.OpenFile(filepath.Join(s.JournalDir, name), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if err != nil {
+			log.Printf("game %q journal: %s", params[0], err)
+			return nil
+		}
+		return file
+	}).(core.Command).Execute()
+}
+
+// registerSnapshots makes games save snapshots to <id>.json files in the
+// snapshot directory, if set.
+func registerSnapshots(s settings) error {
+	err := ioc.Resolve("IoC.Register", "Game.SnapshotInterval", func(params ...interface{}) interface{} {
+		return s.SnapshotPeriod
+	}).(core.Command).Execute()
+	if err != nil {
+		return err
+	}
+
+	if s.SnapshotDir == "" {
+		return nil
+	}
+
+	err = os.MkdirAll(s.SnapshotDir, 0o755)
+	if err != nil {
+		return err
+	}
+
+	return ioc.Resolve("IoC.Register", "Snapshots", func(params ...interface{}) interface{} {
+		id := params[0].(string)
+		err := game.ValidateID(id)
+		if err != nil {
+			log.Printf("snapshot: %s", err)
+			return nil
+		}
+
+		return filepath.Join(s.SnapshotDir, id+".json")
+	}).(core.Command).Execute()
+}
+
+// restoreGames recreates the games of the snapshots in the directory. It
+// returns the ids of the restored games.
+func restoreGames(manager *game.Manager, dir string) (map[string]bool, error) {
+	result := map[string]bool{}
+	if dir == "" {
+		return result, nil
+	}
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, path := range paths {
+		s, err := snapshot.Load(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		_, err = manager.Restore(s)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		result[s.GameID] = true
+		log.Printf("game %q restored from the snapshot taken at %s", s.GameID, s.Taken)
+	}
+
+	return result, nil
+}
+
+// loadGames recreates the games stored in the repository, except for the
+// ones restored from snapshots already. It adds the ids of the loaded games
+// to restored.
+func loadGames(manager *game.Manager, games repository.Repository, restored map[string]bool) error {
+	if games == nil {
+		return nil
+	}
+
+	var ids []string
+	err := games.View(func(tx repository.Tx) (err error) {
+		ids, err = tx.Games()
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		if restored[id] {
+			continue
+		}
+
+		_, err = manager.Load(games, id)
+		if err != nil {
+			return fmt.Errorf("game %q: %w", id, err)
+		}
+		restored[id] = true
+		log.Printf("game %q loaded from the repository", id)
+	}
+
+	return nil
+}
+
+// registerPlayers makes the players recorded with the restored games
+// participants of them. The players of games in the settings are registered
+// again from the settings afterwards.
+func registerPlayers(authService *auth.Service, manager *game.Manager, restored map[string]bool) error {
+	for id := range restored {
+		g, err := manager.Get(id)
+		if err != nil {
+			return err
+		}
+
+		authService.RegisterGame(id, g.Objects().Players())
+	}
+
+	return nil
+}
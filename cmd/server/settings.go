@@ -0,0 +1,175 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"modules/internal/collision"
+	"modules/internal/httpapi"
+	"modules/internal/journal"
+	"modules/internal/object"
+	"modules/internal/repository"
+	"modules/internal/scheduler"
+	"modules/internal/snapshot"
+	"modules/internal/vector"
+	"modules/internal/world"
+)
+
+type settings struct {
+	BufferLength    int              `yaml:"buffer_length"`
+	ShutdownTimeout time.Duration    `yaml:"shutdown_timeout"`
+	IoCConfig       string           `yaml:"ioc_config"`
+	HTTPAddress     string           `yaml:"http_address"`
+	GRPCAddress     string           `yaml:"grpc_address"`
+	MaxBodySize     int64            `yaml:"max_body_size"`
+	WaitTimeout     time.Duration    `yaml:"wait_timeout"`
+	TickInterval    time.Duration    `yaml:"tick_interval"`
+	CellSize        int              `yaml:"collision_cell_size"`
+	JournalDir      string           `yaml:"journal_dir"`
+	Checkpoints     int              `yaml:"journal_checkpoint_interval"`
+	SnapshotDir     string           `yaml:"snapshot_dir"`
+	SnapshotPeriod  time.Duration    `yaml:"snapshot_interval"`
+	Storage         *storageSettings `yaml:"storage"`
+	World           *worldSettings   `yaml:"world"`
+	Auth            *authSettings    `yaml:"auth"`
+	Games           []gameSettings   `yaml:"games"`
+}
+
+type authSettings struct {
+	PrivateKey string        `yaml:"private_key"`
+	PublicKey  string        `yaml:"public_key"`
+	TokenTTL   time.Duration `yaml:"token_ttl"`
+	AdminKey   string        `yaml:"admin_key"`
+}
+
+type storageSettings struct {
+	Type string `yaml:"type"`
+	Path string `yaml:"path"`
+}
+
+type gameSettings struct {
+	ID      string                            `yaml:"id"`
+	Objects map[string]map[string]interface{} `yaml:"objects"`
+	Players map[string][]string               `yaml:"players"`
+	World   *worldSettings                    `yaml:"world"`
+}
+
+type worldSettings struct {
+	Min  []int      `yaml:"min"`
+	Max  []int      `yaml:"max"`
+	Mode world.Mode `yaml:"mode"`
+}
+
+func defaultSettings() settings {
+	return settings{
+		BufferLength:    100,
+		ShutdownTimeout: 10 * time.Second,
+		HTTPAddress:     ":8080",
+		GRPCAddress:     ":8081",
+		MaxBodySize:     httpapi.DefaultMaxBodySize,
+		WaitTimeout:     5 * time.Second,
+		TickInterval:    scheduler.DefaultInterval,
+		CellSize:        collision.DefaultCellSize,
+		Checkpoints:     journal.DefaultCheckpointInterval,
+		SnapshotPeriod:  snapshot.DefaultInterval,
+	}
+}
+
+func loadSettings(path string) (result settings, err error) {
+	result = defaultSettings()
+	if path == "" {
+		return
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+
+	err = yaml.Unmarshal(data, &result)
+	return
+}
+
+func (w *worldSettings) worldMap() (*world.Map, error) {
+	if w == nil {
+		return nil, nil
+	}
+
+	return world.NewMap(vector.New(w.Min), vector.New(w.Max), w.Mode)
+}
+
+func (s *storageSettings) repository() (repository.Repository, error) {
+	if s == nil {
+		return nil, nil
+	}
+
+	switch s.Type {
+	case "memory":
+		return repository.NewMemory(), nil
+	case "bolt":
+		return repository.OpenBolt(s.Path)
+	default:
+		return nil, fmt.Errorf("unknown storage type %q", s.Type)
+	}
+}
+
+// maps returns the maps of the games by id, the default map under the empty
+// id.
+func (s settings) maps() (map[string]*world.Map, error) {
+	result := map[string]*world.Map{}
+	defaultMap, err := s.World.worldMap()
+	if err != nil {
+		return nil, err
+	}
+	if defaultMap != nil {
+		result[""] = defaultMap
+	}
+
+	for _, g := range s.Games {
+		gameMap, err := g.World.worldMap()
+		if err != nil {
+			return nil, fmt.Errorf("game %q: %w", g.ID, err)
+		}
+		if gameMap != nil {
+			result[g.ID] = gameMap
+		}
+	}
+
+	return result, nil
+}
+
+func (g gameSettings) playerIDs() []string {
+	result := make([]string, 0, len(g.Players))
+	for playerID := range g.Players {
+		result = append(result, playerID)
+	}
+
+	return result
+}
+
+func (g gameSettings) registry() (*object.Registry, error) {
+	result := object.NewRegistry()
+	for id, properties := range g.Objects {
+		properties, err := object.ConvertProperties(properties)
+		if err != nil {
+			return nil, err
+		}
+
+		err = result.Add(id, object.New(properties))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for playerID, objectIDs := range g.Players {
+		err := result.SetOwner(playerID, objectIDs...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,189 @@
+package auth
+
+import (
+	"crypto"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"modules/internal/clock"
+	"modules/internal/core"
+	"modules/internal/interpreter"
+)
+
+type Claims struct {
+	GameID   string `json:"game_id"`
+	PlayerID string `json:"player_id"`
+	jwt.RegisteredClaims
+}
+
+// Service issues Ed25519 signed tokens to the participants of games and
+// checks the tokens of player orders.
+type Service struct {
+	privateKey crypto.PrivateKey
+	publicKey  crypto.PublicKey
+	ttl        time.Duration
+	clock      clock.Clock
+
+	mutex        sync.RWMutex
+	participants map[string]map[string]struct{}
+}
+
+func NewService(privateKey crypto.PrivateKey, publicKey crypto.PublicKey, ttl time.Duration) *Service {
+	return &Service{
+		privateKey:   privateKey,
+		publicKey:    publicKey,
+		ttl:          ttl,
+		clock:        clock.NewReal(),
+		participants: map[string]map[string]struct{}{},
+	}
+}
+
+// LoadService reads PEM encoded Ed25519 keys from local files.
+func LoadService(privateKeyPath, publicKeyPath string, ttl time.Duration) (*Service, error) {
+	data, err := os.ReadFile(privateKeyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	privateKey, err := jwt.ParseEdPrivateKeyFromPEM(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %s", ErrInvalidKey, privateKeyPath, err)
+	}
+
+	data, err = os.ReadFile(publicKeyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	publicKey, err := jwt.ParseEdPublicKeyFromPEM(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %s", ErrInvalidKey, publicKeyPath, err)
+	}
+
+	return NewService(privateKey, publicKey, ttl), nil
+}
+
+// SetClock sets the clock tokens are issued and checked by.
+func (s *Service) SetClock(clock clock.Clock) {
+	s.clock = clock
+}
+
+// RegisterGame sets the participants of the game replacing the ones
+// registered before.
+func (s *Service) RegisterGame(gameID string, playerIDs []string) {
+	players := make(map[string]struct{}, len(playerIDs))
+	for _, playerID := range playerIDs {
+		players[playerID] = struct{}{}
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.participants[gameID] = players
+}
+
+func (s *Service) Issue(gameID, playerID string) (string, error) {
+	if !s.isParticipant(gameID, playerID) {
+		return "", fmt.Errorf("%w: %q in %q", ErrUnknownParticipant, playerID, gameID)
+	}
+
+	now := s.clock.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, Claims{
+		GameID:   gameID,
+		PlayerID: playerID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   playerID,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(s.ttl)),
+		},
+	})
+
+	return token.SignedString(s.privateKey)
+}
+
+func (s *Service) Verify(token string) (Claims, error) {
+	var result Claims
+	_, err := jwt.ParseWithClaims(token, &result, func(*jwt.Token) (interface{}, error) {
+		return s.publicKey, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodEdDSA.Alg()}), jwt.WithExpirationRequired(), jwt.WithTimeFunc(s.clock.Now))
+	if err != nil {
+		return Claims{}, fmt.Errorf("%w: %s", ErrInvalidToken, err)
+	}
+
+	return result, nil
+}
+
+// Authorize checks that the token of the message was issued for its game to
+// one of the participants and returns the token claims. Whether the player
+// may control the ordered object is checked by interpreter.InterpretCommand
+// since ownership is kept by the objects.
+func (s *Service) Authorize(message interpreter.Message) (Claims, error) {
+	if message.Token == "" {
+		return Claims{}, fmt.Errorf("%w: no token", ErrInvalidToken)
+	}
+
+	claims, err := s.Verify(message.Token)
+	if err != nil {
+		return Claims{}, err
+	}
+
+	if claims.GameID != message.GameID {
+		return Claims{}, fmt.Errorf("%w: token is issued for game %q", ErrForbidden, claims.GameID)
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if _, ok := s.participants[claims.GameID][claims.PlayerID]; !ok {
+		return Claims{}, fmt.Errorf("%w: %q in %q", ErrUnknownParticipant, claims.PlayerID, claims.GameID)
+	}
+
+	return claims, nil
+}
+
+// Wrap makes the order command execute only when the token of the message
+// is authorized. It fits game.Manager.SetOrderWrapper.
+func (s *Service) Wrap(message interpreter.Message, command core.Command) core.Command {
+	return NewAuthorizeCommand(s, message, command)
+}
+
+func (s *Service) isParticipant(gameID, playerID string) bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	_, ok := s.participants[gameID][playerID]
+	return ok
+}
+
+// AuthorizeCommand tells the authorized player to the wrapped command if it
+// has a SetPlayerID method like interpreter.InterpretCommand.
+type AuthorizeCommand struct {
+	service *Service
+	message interpreter.Message
+	command core.Command
+}
+
+func NewAuthorizeCommand(service *Service, message interpreter.Message, command core.Command) *AuthorizeCommand {
+	return &AuthorizeCommand{
+		service: service,
+		message: message,
+		command: command,
+	}
+}
+
+func (c *AuthorizeCommand) Execute() error {
+	claims, err := c.service.Authorize(c.message)
+	if err != nil {
+		return err
+	}
+
+	if identified, ok := c.command.(interface{ SetPlayerID(string) }); ok {
+		identified.SetPlayerID(claims.PlayerID)
+	}
+
+	return c.command.Execute()
+}
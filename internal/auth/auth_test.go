@@ -0,0 +1,134 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"modules/internal/clock"
+	"modules/internal/interpreter"
+	"modules/internal/mock"
+)
+
+func TestAuth(t *testing.T) {
+	suite.Run(t, new(AuthTestSuite))
+}
+
+type AuthTestSuite struct {
+	suite.Suite
+
+	service *Service
+}
+
+func (s *AuthTestSuite) SetupTest() {
+	var err error
+	s.service, err = LoadService("testdata/private.pem", "testdata/public.pem", time.Hour)
+	s.Require().NoError(err)
+
+	s.service.RegisterGame("game1", []string{"alice", "bob"})
+	s.service.RegisterGame("game2", []string{"bob"})
+}
+
+func (s *AuthTestSuite) issue(gameID, playerID string) string {
+	token, err := s.service.Issue(gameID, playerID)
+	s.Require().NoError(err)
+	return token
+}
+
+func (s *AuthTestSuite) TestVerify() {
+	claims, err := s.service.Verify(s.issue("game1", "alice"))
+	s.Require().NoError(err)
+	s.Require().Equal("game1", claims.GameID)
+	s.Require().Equal("alice", claims.PlayerID)
+
+	_, err = s.service.Issue("game1", "carol")
+	s.Require().ErrorIs(err, ErrUnknownParticipant)
+
+	_, err = s.service.Issue("game3", "alice")
+	s.Require().ErrorIs(err, ErrUnknownParticipant)
+}
+
+func (s *AuthTestSuite) TestInvalidToken() {
+	token := s.issue("game1", "alice")
+	_, err := s.service.Verify(token[:len(token)-2] + "xx")
+	s.Require().ErrorIs(err, ErrInvalidToken)
+
+	_, err = s.service.Verify("token")
+	s.Require().ErrorIs(err, ErrInvalidToken)
+
+	fake := clock.NewFake(time.Now())
+	s.service.SetClock(fake)
+	token = s.issue("game1", "alice")
+	fake.Advance(time.Hour - time.Second)
+	_, err = s.service.Verify(token)
+	s.Require().NoError(err)
+	fake.Advance(time.Second)
+	_, err = s.service.Verify(token)
+	s.Require().ErrorIs(err, ErrInvalidToken)
+}
+
+func (s *AuthTestSuite) TestAuthorize() {
+	alice := s.issue("game1", "alice")
+
+	tests := []struct {
+		message interpreter.Message
+		err     error
+	}{
+		{interpreter.Message{GameID: "game1", ObjectID: "548", Token: alice}, nil},
+		{interpreter.Message{GameID: "game2", ObjectID: "548", Token: alice}, ErrForbidden},
+		{interpreter.Message{GameID: "game1", ObjectID: "548"}, ErrInvalidToken},
+		{interpreter.Message{GameID: "game2", ObjectID: "548", Token: s.issue("game2", "bob")}, nil},
+	}
+
+	for _, test := range tests {
+		_, err := s.service.Authorize(test.message)
+		if test.err == nil {
+			s.Require().NoError(err, test.message)
+		} else {
+			s.Require().ErrorIs(err, test.err, test.message)
+		}
+	}
+
+	s.service.RegisterGame("game1", []string{"bob"})
+	_, err := s.service.Authorize(interpreter.Message{GameID: "game1", ObjectID: "548", Token: alice})
+	s.Require().ErrorIs(err, ErrUnknownParticipant)
+}
+
+type identifiedCommand struct {
+	mock.CommandMock
+
+	playerID string
+}
+
+func (c *identifiedCommand) SetPlayerID(playerID string) {
+	c.playerID = playerID
+}
+
+func (s *AuthTestSuite) TestAuthorizeCommand() {
+	command := identifiedCommand{}
+	command.On("Execute").Return(nil)
+
+	message := interpreter.Message{GameID: "game2", ObjectID: "548", Token: s.issue("game1", "alice")}
+	err := s.service.Wrap(message, &command).Execute()
+	s.Require().ErrorIs(err, ErrForbidden)
+	command.AssertNotCalled(s.T(), "Execute")
+	s.Require().Empty(command.playerID)
+
+	message.GameID = "game1"
+	err = s.service.Wrap(message, &command).Execute()
+	s.Require().NoError(err)
+	command.AssertCalled(s.T(), "Execute")
+	s.Require().Equal("alice", command.playerID)
+}
+
+func (s *AuthTestSuite) TestLoadService() {
+	_, err := LoadService("testdata/public.pem", "testdata/public.pem", time.Hour)
+	s.Require().ErrorIs(err, ErrInvalidKey)
+
+	_, err = LoadService("testdata/private.pem", "testdata/private.pem", time.Hour)
+	s.Require().ErrorIs(err, ErrInvalidKey)
+
+	_, err = LoadService("testdata/none.pem", "testdata/public.pem", time.Hour)
+	s.Require().Error(err)
+}
@@ -0,0 +1,13 @@
+package auth
+
+import "fmt"
+
+var (
+	ErrInvalidKey = fmt.Errorf("invalid key")
+
+	ErrInvalidToken = fmt.Errorf("invalid token")
+
+	ErrUnknownParticipant = fmt.Errorf("player is not a participant of the game")
+
+	ErrForbidden = fmt.Errorf("forbidden")
+)
@@ -0,0 +1,173 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Fake is a clock moving only on Advance. Timers and tickers fire during
+// Advance in the order they are due, each one as many times as it is due.
+// Like the channels of the time package, their channels hold one value and
+// values nobody received in time are dropped.
+type Fake struct {
+	mutex  sync.Mutex
+	cond   *sync.Cond
+	now    time.Time
+	timers []*fakeTimer
+}
+
+func NewFake(now time.Time) *Fake {
+	result := &Fake{now: now}
+	result.cond = sync.NewCond(&result.mutex)
+	return result
+}
+
+func (f *Fake) Now() time.Time {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	return f.now
+}
+
+func (f *Fake) After(d time.Duration) <-chan time.Time {
+	return f.NewTimer(d).C()
+}
+
+func (f *Fake) NewTimer(d time.Duration) Timer {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	result := &fakeTimer{clock: f, c: make(chan time.Time, 1)}
+	f.start(result, d)
+	return result
+}
+
+func (f *Fake) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("clock: non-positive interval for NewTicker")
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	result := &fakeTimer{clock: f, c: make(chan time.Time, 1), period: d}
+	f.start(result, d)
+	return fakeTicker{result}
+}
+
+// Advance moves the clock forward firing the timers and tickers that are due.
+func (f *Fake) Advance(d time.Duration) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	target := f.now.Add(d)
+	for {
+		next := -1
+		for i, t := range f.timers {
+			if !t.when.After(target) && (next == -1 || t.when.Before(f.timers[next].when)) {
+				next = i
+			}
+		}
+		if next == -1 {
+			break
+		}
+
+		t := f.timers[next]
+		f.now = t.when
+		t.send(f.now)
+		if t.period > 0 {
+			t.when = t.when.Add(t.period)
+		} else {
+			f.remove(t)
+		}
+	}
+	f.now = target
+}
+
+// Timers returns the number of timers and tickers that have not fired or
+// been stopped yet.
+func (f *Fake) Timers() int {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	return len(f.timers)
+}
+
+// BlockUntil waits until there are at least n timers and tickers, e.g. until
+// the goroutine under test has started waiting for one before Advance.
+func (f *Fake) BlockUntil(n int) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	for len(f.timers) < n {
+		f.cond.Wait()
+	}
+}
+
+// start must be called with the mutex held.
+func (f *Fake) start(t *fakeTimer, d time.Duration) {
+	t.when = f.now.Add(d)
+	if d <= 0 {
+		t.send(f.now)
+		return
+	}
+
+	f.timers = append(f.timers, t)
+	f.cond.Broadcast()
+}
+
+// remove must be called with the mutex held.
+func (f *Fake) remove(t *fakeTimer) bool {
+	for i := range f.timers {
+		if f.timers[i] == t {
+			f.timers = append(f.timers[:i], f.timers[i+1:]...)
+			f.cond.Broadcast()
+			return true
+		}
+	}
+
+	return false
+}
+
+// fakeTimer fires once unless it has a period, which tickers have.
+type fakeTimer struct {
+	clock  *Fake
+	c      chan time.Time
+	when   time.Time
+	period time.Duration
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mutex.Lock()
+	defer t.clock.mutex.Unlock()
+
+	return t.clock.remove(t)
+}
+
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	t.clock.mutex.Lock()
+	defer t.clock.mutex.Unlock()
+
+	active := t.clock.remove(t)
+	t.clock.start(t, d)
+	return active
+}
+
+func (t *fakeTimer) send(now time.Time) {
+	select {
+	case t.c <- now:
+	default:
+	}
+}
+
+type fakeTicker struct {
+	*fakeTimer
+}
+
+func (t fakeTicker) Stop() {
+	t.fakeTimer.Stop()
+}
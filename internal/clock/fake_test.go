@@ -0,0 +1,88 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestFake(t *testing.T) {
+	suite.Run(t, new(FakeTestSuite))
+}
+
+type FakeTestSuite struct {
+	suite.Suite
+
+	clock *Fake
+}
+
+func (s *FakeTestSuite) SetupTest() {
+	s.clock = NewFake(start)
+}
+
+func (s *FakeTestSuite) TestTimer() {
+	timer := s.clock.NewTimer(time.Second)
+	after := s.clock.After(2 * time.Second)
+	s.Require().Equal(2, s.clock.Timers())
+
+	s.clock.Advance(999 * time.Millisecond)
+	s.Require().Empty(timer.C())
+
+	s.clock.Advance(time.Millisecond)
+	s.Require().Equal(start.Add(time.Second), <-timer.C())
+	s.Require().False(timer.Stop())
+	s.Require().Empty(after)
+
+	s.clock.Advance(time.Hour)
+	s.Require().Equal(start.Add(2*time.Second), <-after)
+	s.Require().Equal(start.Add(time.Hour+time.Second), s.clock.Now())
+	s.Require().Zero(s.clock.Timers())
+}
+
+func (s *FakeTestSuite) TestStopReset() {
+	timer := s.clock.NewTimer(time.Second)
+	s.Require().True(timer.Stop())
+	s.clock.Advance(time.Second)
+	s.Require().Empty(timer.C())
+
+	s.Require().False(timer.Reset(time.Second))
+	s.Require().True(timer.Reset(2 * time.Second))
+	s.clock.Advance(time.Second)
+	s.Require().Empty(timer.C())
+	s.clock.Advance(time.Second)
+	s.Require().Equal(start.Add(3*time.Second), <-timer.C())
+
+	s.Require().False(timer.Reset(0))
+	s.Require().Equal(start.Add(3*time.Second), <-timer.C())
+}
+
+func (s *FakeTestSuite) TestTicker() {
+	ticker := s.clock.NewTicker(time.Second)
+
+	s.clock.Advance(time.Second)
+	s.Require().Equal(start.Add(time.Second), <-ticker.C())
+
+	// ticks nobody received are dropped
+	s.clock.Advance(3 * time.Second)
+	s.Require().Equal(start.Add(2*time.Second), <-ticker.C())
+	s.Require().Empty(ticker.C())
+
+	ticker.Stop()
+	s.clock.Advance(time.Second)
+	s.Require().Empty(ticker.C())
+	s.Require().Panics(func() { s.clock.NewTicker(0) })
+}
+
+func (s *FakeTestSuite) TestBlockUntil() {
+	done := make(chan time.Time)
+	go func() {
+		done <- <-s.clock.After(time.Second)
+	}()
+
+	s.clock.BlockUntil(1)
+	s.clock.Advance(time.Second)
+	s.Require().Equal(start.Add(time.Second), <-done)
+}
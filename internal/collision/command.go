@@ -0,0 +1,74 @@
+package collision
+
+import (
+	"modules/internal/command"
+	"modules/internal/core"
+)
+
+// UpdateCommand moves the object in the detector to its current position and
+// reports its overlaps to the detector handler. It follows the commands
+// moving the object.
+type UpdateCommand struct {
+	detector *Detector
+	key      interface{}
+	object   core.Collidable
+}
+
+func NewUpdateCommand(detector *Detector, key interface{}, object core.Collidable) *UpdateCommand {
+	return &UpdateCommand{
+		detector: detector,
+		key:      key,
+		object:   object,
+	}
+}
+
+func (c *UpdateCommand) Execute() error {
+	position, err := c.object.GetPosition()
+	if err != nil {
+		return err
+	}
+
+	radius, err := c.object.GetRadius()
+	if err != nil {
+		return err
+	}
+
+	others, err := c.detector.Update(c.key, position, radius)
+	if err != nil {
+		return err
+	}
+
+	for _, other := range others {
+		c.detector.handler(Collision{A: c.key, B: other})
+	}
+
+	return nil
+}
+
+// RegisterCodec makes the codec encode update commands as
+// "collision_update", decoded into updates of the detector, and the commands
+// destroying objects after a collision as "destroy", decoded into the
+// commands remove returns for the objects.
+func RegisterCodec(codec *command.Codec, detector *Detector, remove func(target core.Object) core.Command) {
+	codec.Register("collision_update", &UpdateCommand{}, func(c *command.Codec, cmd core.Command) (command.DTO, error) {
+		id, err := c.ObjectID(cmd.(*UpdateCommand).key)
+		return command.DTO{ObjectID: id}, err
+	}, func(c *command.Codec, dto command.DTO) (core.Command, error) {
+		target, err := c.Object(dto)
+		if err != nil {
+			return nil, err
+		}
+		return NewUpdateCommand(detector, target.Object(), target), nil
+	})
+
+	codec.Register("destroy", &destroyCommand{}, func(c *command.Codec, cmd core.Command) (command.DTO, error) {
+		id, err := c.ObjectID(cmd.(*destroyCommand).target)
+		return command.DTO{ObjectID: id}, err
+	}, func(c *command.Codec, dto command.DTO) (core.Command, error) {
+		target, err := c.Object(dto)
+		if err != nil {
+			return nil, err
+		}
+		return remove(target.Object()), nil
+	})
+}
@@ -0,0 +1,184 @@
+package collision
+
+import (
+	"fmt"
+	"sync"
+
+	"modules/internal/vector"
+)
+
+const DefaultCellSize = 64
+
+// Collision reports that the object A moved into an overlap with B.
+type Collision struct {
+	A interface{}
+	B interface{}
+}
+
+type entry struct {
+	key      interface{}
+	position vector.Vector
+	radius   int
+	cell     Cell
+	index    int
+}
+
+// Detector keeps objects, spheres given by a position and a radius, in the
+// cells of a uniform grid. Overlaps are looked for in the 2^n cells around
+// the moved object picked by the cell of a second grid offset by half a
+// cell, which finds every overlap of objects whose radii add up to at most
+// half of the cell size. Objects of a larger radius than MaxRadius are
+// refused, they could miss collisions.
+type Detector struct {
+	grid       Grid
+	offsetGrid Grid
+	maxRadius  int
+
+	mutex   sync.Mutex
+	entries map[interface{}]*entry
+	cells   map[Cell][]*entry
+	handler func(Collision)
+}
+
+func NewDetector(cellSize int) (*Detector, error) {
+	grid, err := NewGrid(cellSize, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	offsetGrid, err := NewGrid(cellSize, cellSize/2)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Detector{
+		grid:       grid,
+		offsetGrid: offsetGrid,
+		maxRadius:  cellSize / 4,
+		entries:    map[interface{}]*entry{},
+		cells:      map[Cell][]*entry{},
+		handler:    func(Collision) {},
+	}, nil
+}
+
+// SetHandler sets the function UpdateCommand reports collisions to.
+func (d *Detector) SetHandler(handler func(Collision)) {
+	d.handler = handler
+}
+
+// MaxRadius is the largest radius of the objects the detector takes.
+func (d *Detector) MaxRadius() int {
+	return d.maxRadius
+}
+
+// CellSizeFor returns the smallest cell size for objects of the radius.
+func CellSizeFor(radius int) int {
+	return 4 * radius
+}
+
+func (d *Detector) Len() int {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return len(d.entries)
+}
+
+// Update moves the object keyed by key to the position, adding it if
+// unknown, and returns the keys of the objects it overlaps.
+func (d *Detector) Update(key interface{}, position vector.Vector, radius int) ([]interface{}, error) {
+	if radius > d.maxRadius {
+		return nil, fmt.Errorf("%w: %d, at most %d", ErrRadiusTooLarge, radius, d.maxRadius)
+	}
+
+	cell, err := d.grid.Cell(position)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := d.offsetGrid.Cell(position)
+	if err != nil {
+		return nil, err
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	e, ok := d.entries[key]
+	if !ok {
+		e = &entry{key: key, cell: cell}
+		d.entries[key] = e
+		d.insert(e)
+	} else if e.cell != cell {
+		d.remove(e)
+		e.cell = cell
+		d.insert(e)
+	}
+	e.position = position
+	e.radius = radius
+
+	return d.overlapping(e, block, len(position)), nil
+}
+
+func (d *Detector) Remove(key interface{}) bool {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	e, ok := d.entries[key]
+	if !ok {
+		return false
+	}
+
+	delete(d.entries, key)
+	d.remove(e)
+	return true
+}
+
+// overlapping looks through the cells from the block index to the next one
+// along each of the first dimensions axes.
+func (d *Detector) overlapping(e *entry, block Cell, dimensions int) []interface{} {
+	var result []interface{}
+	for i := 0; i < 1<<dimensions; i++ {
+		cell := block
+		for axis := 0; axis < dimensions; axis++ {
+			cell[axis] += (i >> axis) & 1
+		}
+
+		for _, other := range d.cells[cell] {
+			if other != e && overlap(e, other) {
+				result = append(result, other.key)
+			}
+		}
+	}
+
+	return result
+}
+
+func (d *Detector) insert(e *entry) {
+	e.index = len(d.cells[e.cell])
+	d.cells[e.cell] = append(d.cells[e.cell], e)
+}
+
+func (d *Detector) remove(e *entry) {
+	entries := d.cells[e.cell]
+	last := len(entries) - 1
+	entries[e.index] = entries[last]
+	entries[e.index].index = e.index
+	entries[last] = nil
+
+	if last == 0 {
+		delete(d.cells, e.cell)
+	} else {
+		d.cells[e.cell] = entries[:last]
+	}
+}
+
+func overlap(a, b *entry) bool {
+	var distance int64
+	for i := 0; i < len(a.position) && i < len(b.position); i++ {
+		delta := int64(a.position[i] - b.position[i])
+		distance += delta * delta
+	}
+
+	radii := int64(a.radius + b.radius)
+	return distance <= radii*radii
+}
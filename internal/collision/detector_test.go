@@ -0,0 +1,182 @@
+package collision
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"modules/internal/mock"
+	"modules/internal/vector"
+)
+
+func TestDetector(t *testing.T) {
+	suite.Run(t, new(DetectorTestSuite))
+}
+
+type DetectorTestSuite struct {
+	suite.Suite
+
+	detector *Detector
+}
+
+func (s *DetectorTestSuite) SetupTest() {
+	var err error
+	s.detector, err = NewDetector(10)
+	s.Require().NoError(err)
+}
+
+func (s *DetectorTestSuite) update(key string, position []int, radius int) []interface{} {
+	result, err := s.detector.Update(key, vector.New(position), radius)
+	s.Require().NoError(err)
+	return result
+}
+
+func (s *DetectorTestSuite) TestGrid() {
+	grid, err := NewGrid(10, 5)
+	s.Require().NoError(err)
+
+	cell, err := grid.Cell(vector.New([]int{4, 5, -6, -16}))
+	s.Require().NoError(err)
+	s.Require().Equal(Cell{-1, 0, -2, -3}, cell)
+
+	_, err = grid.Cell(vector.New([]int{1, 2, 3, 4, 5}))
+	s.Require().ErrorIs(err, ErrTooManyDimensions)
+	_, err = NewGrid(0, 0)
+	s.Require().ErrorIs(err, ErrInvalidCellSize)
+}
+
+func (s *DetectorTestSuite) TestUpdate() {
+	s.Require().Empty(s.update("a", []int{0, 0}, 2))
+	s.Require().Empty(s.update("b", []int{5, 0}, 2))
+	s.Require().Equal([]interface{}{"a"}, s.update("b", []int{4, 0}, 2))
+	s.Require().Equal([]interface{}{"b"}, s.update("a", []int{0, 0}, 2))
+
+	// on both sides of a cell border
+	s.Require().Empty(s.update("c", []int{29, 9}, 1))
+	s.Require().Equal([]interface{}{"c"}, s.update("d", []int{30, 10}, 1))
+	s.Require().Empty(s.update("d", []int{31, 11}, 1))
+
+	s.Require().Equal(4, s.detector.Len())
+	s.Require().True(s.detector.Remove("a"))
+	s.Require().False(s.detector.Remove("a"))
+	s.Require().Empty(s.update("b", []int{4, 0}, 2))
+}
+
+func (s *DetectorTestSuite) TestLargeRadius() {
+	_, err := s.detector.Update("a", vector.New([]int{0, 0}), 3)
+	s.Require().ErrorIs(err, ErrRadiusTooLarge)
+	s.Require().Zero(s.detector.Len())
+
+	s.detector, err = NewDetector(CellSizeFor(20))
+	s.Require().NoError(err)
+	s.Require().Empty(s.update("a", []int{0, 0}, 20))
+	s.Require().Equal([]interface{}{"a"}, s.update("b", []int{39, 0}, 20))
+}
+
+func (s *DetectorTestSuite) TestRandom() {
+	const n, size, radius = 300, 200, 2
+	random := rand.New(rand.NewSource(1))
+	positions := make([]vector.Vector, n)
+	for i := range positions {
+		positions[i] = vector.New([]int{random.Intn(size) - size/2, random.Intn(size) - size/2})
+		_, err := s.detector.Update(i, positions[i], radius)
+		s.Require().NoError(err)
+	}
+
+	for i := range positions {
+		var expected []int
+		for j := range positions {
+			dx, dy := positions[i][0]-positions[j][0], positions[i][1]-positions[j][1]
+			if i != j && dx*dx+dy*dy <= 4*radius*radius {
+				expected = append(expected, j)
+			}
+		}
+
+		found, err := s.detector.Update(i, positions[i], radius)
+		s.Require().NoError(err)
+		actual := make([]int, len(found))
+		for k, key := range found {
+			actual[k] = key.(int)
+		}
+		s.Require().ElementsMatch(expected, actual, "object %d at %v", i, positions[i])
+	}
+}
+
+func (s *DetectorTestSuite) TestUpdateCommand() {
+	var collisions []Collision
+	s.detector.SetHandler(func(c Collision) {
+		collisions = append(collisions, c)
+	})
+	s.update("a", []int{0, 0}, 1)
+	s.update("b", []int{3, 0}, 1)
+
+	movable := mock.MovableMock{}
+	movable.On("GetPosition").Return(vector.New([]int{1, 1}), nil)
+	object := struct {
+		*mock.MovableMock
+		radius
+	}{&movable, 1}
+
+	s.Require().NoError(NewUpdateCommand(s.detector, "c", object).Execute())
+	s.Require().Equal([]Collision{{A: "c", B: "a"}}, collisions)
+	s.Require().Equal(3, s.detector.Len())
+}
+
+type radius int
+
+func (r radius) GetRadius() (int, error) {
+	return int(r), nil
+}
+
+func benchmarkObjects(n, size int) []vector.Vector {
+	random := rand.New(rand.NewSource(1))
+	result := make([]vector.Vector, n)
+	for i := range result {
+		result[i] = vector.New([]int{random.Intn(size), random.Intn(size)})
+	}
+	return result
+}
+
+// BenchmarkUpdate10k moves one of 10k objects in a 5000x5000 world.
+func BenchmarkUpdate10k(b *testing.B) {
+	positions := benchmarkObjects(10000, 5000)
+	detector, err := NewDetector(DefaultCellSize)
+	if err != nil {
+		b.Fatal(err)
+	}
+	for i, position := range positions {
+		_, _ = detector.Update(i, position, 8)
+	}
+
+	velocity := vector.New([]int{3, -2})
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		i := n % len(positions)
+		positions[i] = vector.Add(positions[i], velocity)
+		_, err = detector.Update(i, positions[i], 8)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkBruteForce10k checks the overlaps of one object with 10k objects
+// without a grid for comparison with BenchmarkUpdate10k.
+func BenchmarkBruteForce10k(b *testing.B) {
+	positions := benchmarkObjects(10000, 5000)
+	entries := make([]entry, len(positions))
+	for i, position := range positions {
+		entries[i] = entry{key: i, position: position, radius: 8}
+	}
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		e := &entries[n%len(entries)]
+		for i := range entries {
+			if &entries[i] != e {
+				overlap(e, &entries[i])
+			}
+		}
+	}
+}
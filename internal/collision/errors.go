@@ -0,0 +1,13 @@
+package collision
+
+import "fmt"
+
+var (
+	ErrTooManyDimensions = fmt.Errorf("too many dimensions")
+
+	ErrInvalidCellSize = fmt.Errorf("invalid cell size")
+
+	ErrRadiusTooLarge = fmt.Errorf("radius too large for the cell size")
+
+	ErrNoGame = fmt.Errorf("no game to remove the object from")
+)
@@ -0,0 +1,51 @@
+package collision
+
+import (
+	"fmt"
+
+	"modules/internal/vector"
+)
+
+// MaxDimensions is the largest number of position coordinates a grid
+// supports.
+const MaxDimensions = 4
+
+// Cell identifies a cell of a grid by its index along every axis. Axes
+// beyond the dimension of the position are zero.
+type Cell [MaxDimensions]int
+
+// Grid is a uniform grid of cubic cells shifted by the offset along every
+// axis.
+type Grid struct {
+	size   int
+	offset int
+}
+
+func NewGrid(size, offset int) (Grid, error) {
+	if size <= 0 {
+		return Grid{}, fmt.Errorf("%w: %d", ErrInvalidCellSize, size)
+	}
+
+	return Grid{size: size, offset: offset}, nil
+}
+
+func (g Grid) Cell(position vector.Vector) (Cell, error) {
+	var result Cell
+	if len(position) > MaxDimensions {
+		return result, fmt.Errorf("%w: %d", ErrTooManyDimensions, len(position))
+	}
+
+	for i, x := range position {
+		result[i] = floorDiv(x-g.offset, g.size)
+	}
+
+	return result, nil
+}
+
+func floorDiv(a, b int) int {
+	result := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		result--
+	}
+	return result
+}
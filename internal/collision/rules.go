@@ -0,0 +1,122 @@
+package collision
+
+import (
+	"modules/internal/command"
+	"modules/internal/core"
+	"modules/internal/ioc"
+	"modules/internal/object"
+)
+
+// rules are the default responses to collisions by the kinds of the objects.
+// The first object of a rule has the first kind.
+var rules = map[string]func(a, b core.Object) core.Command{
+	object.KindShip + "." + object.KindShip: func(a, b core.Object) core.Command {
+		return command.NewMacroCommand(
+			command.NewBounceCommand(object.NewAdapter(a), object.NewAdapter(b)),
+			command.NewBounceCommand(object.NewAdapter(b), object.NewAdapter(a)),
+		)
+	},
+	object.KindShip + "." + object.KindProjectile: func(ship, projectile core.Object) core.Command {
+		if sameOwner(ship, projectile) || firedBy(projectile, ship) {
+			return nil
+		}
+
+		return command.NewMacroCommand(
+			command.NewDamageCommand(object.NewAdapter(ship), object.NewAdapter(projectile), destroy(ship)),
+			destroy(projectile),
+		)
+	},
+	object.KindShip + "." + object.KindAsteroid: func(ship, asteroid core.Object) core.Command {
+		return command.NewMacroCommand(
+			command.NewBounceCommand(object.NewAdapter(ship), object.NewAdapter(asteroid)),
+			command.NewDamageCommand(object.NewAdapter(ship), object.NewAdapter(asteroid), destroy(ship)),
+		)
+	},
+	object.KindProjectile + "." + object.KindAsteroid: func(projectile, asteroid core.Object) core.Command {
+		return destroy(projectile)
+	},
+}
+
+// sameOwner tells if both objects are owned by the same player, so that
+// a projectile passes through the ships of its shooter.
+func sameOwner(a, b core.Object) bool {
+	ownerA, err := object.NewAdapter(a).GetOwner()
+	if err != nil || ownerA == "" {
+		return false
+	}
+
+	ownerB, err := object.NewAdapter(b).GetOwner()
+	return err == nil && ownerA == ownerB
+}
+
+// firedBy tells if the ship fired the projectile, which starts at the
+// position of the ship.
+func firedBy(projectile, ship core.Object) bool {
+	shooter, err := object.NewAdapter(projectile).GetShooter()
+	if err != nil || shooter == "" {
+		return false
+	}
+
+	identified, ok := ship.(interface{ ID() string })
+	return ok && identified.ID() == shooter
+}
+
+func destroy(target core.Object) core.Command {
+	return &destroyCommand{target: target}
+}
+
+// destroyCommand removes the object from the game with "Game.RemoveObject"
+// resolved on execution, when the listener runs in the game scope.
+type destroyCommand struct {
+	target core.Object
+}
+
+func (c *destroyCommand) Execute() error {
+	remove, ok := ioc.Resolve("Game.RemoveObject", c.target).(core.Command)
+	if !ok {
+		return ErrNoGame
+	}
+
+	return remove.Execute()
+}
+
+// RegisterRules registers the default collision responses as
+// "Collisions.<kind>.<kind>" in the current scope. A response is resolved
+// with the two objects, the first one of the first kind.
+func RegisterRules() error {
+	for key, create := range rules {
+		create := create
+		err := ioc.Resolve("IoC.Register", "Collisions."+key, func(params ...interface{}) interface{} {
+			return create(params[0].(core.Object), params[1].(core.Object))
+		}).(core.Command).Execute()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Response resolves the response to the collision of the objects by their
+// kinds in either order. It returns nil if the objects do not interact.
+func Response(a, b core.Object) (core.Command, error) {
+	kindA, err := object.NewAdapter(a).GetKind()
+	if err != nil {
+		return nil, err
+	}
+
+	kindB, err := object.NewAdapter(b).GetKind()
+	if err != nil {
+		return nil, err
+	}
+
+	if result, ok := ioc.Resolve("Collisions."+kindA+"."+kindB, a, b).(core.Command); ok {
+		return result, nil
+	}
+
+	if result, ok := ioc.Resolve("Collisions."+kindB+"."+kindA, b, a).(core.Command); ok {
+		return result, nil
+	}
+
+	return nil, nil
+}
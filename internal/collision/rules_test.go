@@ -0,0 +1,149 @@
+package collision
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"modules/internal/core"
+	"modules/internal/ioc"
+	"modules/internal/object"
+	"modules/internal/vector"
+)
+
+func TestRules(t *testing.T) {
+	suite.Run(t, new(RulesTestSuite))
+}
+
+type RulesTestSuite struct {
+	suite.Suite
+
+	removed []core.Object
+}
+
+func (s *RulesTestSuite) execute(key string, params ...interface{}) {
+	s.Require().NoError(ioc.Resolve(key, params...).(core.Command).Execute())
+}
+
+func (s *RulesTestSuite) SetupSuite() {
+	s.execute("Scopes.New", "collision_rules")
+	s.Require().NoError(RegisterRules())
+	s.execute("IoC.Register", "Game.RemoveObject", func(params ...interface{}) interface{} {
+		return &removeCommand{suite: s, object: params[0].(core.Object)}
+	})
+}
+
+func (s *RulesTestSuite) SetupTest() {
+	s.execute("Scopes.Current", "collision_rules")
+	s.removed = nil
+}
+
+type removeCommand struct {
+	suite  *RulesTestSuite
+	object core.Object
+}
+
+func (c *removeCommand) Execute() error {
+	c.suite.removed = append(c.suite.removed, c.object)
+	return nil
+}
+
+func (s *RulesTestSuite) respond(a, b core.Object) {
+	response, err := Response(a, b)
+	s.Require().NoError(err)
+	s.Require().NotNil(response)
+	s.Require().NoError(response.Execute())
+}
+
+func (s *RulesTestSuite) TestProjectile() {
+	ship := object.New(map[string]interface{}{
+		object.Health: 2,
+	})
+	projectile := object.New(map[string]interface{}{
+		object.Kind: object.KindProjectile,
+	})
+
+	s.respond(projectile, ship)
+	s.Require().Equal(1, ship.Properties()[object.Health])
+	s.Require().Equal([]core.Object{projectile}, s.removed)
+
+	s.respond(ship, projectile)
+	s.Require().Equal(0, ship.Properties()[object.Health])
+	s.Require().Equal([]core.Object{projectile, ship, projectile}, s.removed)
+}
+
+func (s *RulesTestSuite) TestOwnProjectile() {
+	ship := object.New(map[string]interface{}{
+		object.Health: 2,
+		object.Owner:  "alice",
+	})
+	projectile := object.New(map[string]interface{}{
+		object.Kind:  object.KindProjectile,
+		object.Owner: "alice",
+	})
+
+	response, err := Response(projectile, ship)
+	s.Require().NoError(err)
+	s.Require().Nil(response)
+	s.Require().Equal(2, ship.Properties()[object.Health])
+
+	s.Require().NoError(projectile.SetProperty(object.Owner, "bob"))
+	s.respond(projectile, ship)
+	s.Require().Equal(1, ship.Properties()[object.Health])
+}
+
+func (s *RulesTestSuite) TestShooterProjectile() {
+	objects := object.NewRegistry()
+	ship := object.New(map[string]interface{}{object.Health: 2})
+	other := object.New(map[string]interface{}{object.Health: 2})
+	s.Require().NoError(objects.Add("548", ship))
+	s.Require().NoError(objects.Add("549", other))
+	projectile := object.New(map[string]interface{}{
+		object.Kind:    object.KindProjectile,
+		object.Shooter: "548",
+	})
+
+	response, err := Response(ship, projectile)
+	s.Require().NoError(err)
+	s.Require().Nil(response)
+
+	s.respond(other, projectile)
+	s.Require().Equal(1, other.Properties()[object.Health])
+	s.Require().Equal(2, ship.Properties()[object.Health])
+}
+
+func (s *RulesTestSuite) TestBounce() {
+	ship := object.New(map[string]interface{}{
+		object.Position: vector.New([]int{0, 0}),
+		object.Velocity: vector.New([]int{3, 4}),
+	})
+	other := object.New(map[string]interface{}{
+		object.Kind:     object.KindShip,
+		object.Position: vector.New([]int{2, 0}),
+		object.Velocity: vector.New([]int{-1, 0}),
+	})
+
+	s.respond(ship, other)
+	s.Require().Equal(vector.New([]int{-3, 4}), ship.Properties()[object.Velocity])
+	s.Require().Equal(vector.New([]int{1, 0}), other.Properties()[object.Velocity])
+	s.Require().Empty(s.removed)
+}
+
+func (s *RulesTestSuite) TestNoRule() {
+	a := object.New(map[string]interface{}{object.Kind: object.KindAsteroid})
+	b := object.New(map[string]interface{}{object.Kind: object.KindAsteroid})
+	response, err := Response(a, b)
+	s.Require().NoError(err)
+	s.Require().Nil(response)
+
+	_, err = Response(a, object.New(map[string]interface{}{object.Kind: 1}))
+	s.Require().ErrorIs(err, object.ErrInvalidProperty)
+}
+
+func (s *RulesTestSuite) TestNoGame() {
+	errChan := make(chan error)
+	go func() {
+		errChan <- destroy(object.New(nil)).Execute()
+	}()
+	s.Require().ErrorIs(<-errChan, ErrNoGame)
+}
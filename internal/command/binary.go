@@ -0,0 +1,165 @@
+package command
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+)
+
+// binaryVersion starts every binary DTO so that the format can change.
+const binaryVersion = 1
+
+// MarshalBinaryDTO encodes the DTO compactly: strings are prefixed by their
+// length, numbers are varints and the parameters and arguments are sorted by
+// name.
+func MarshalBinaryDTO(dto DTO) []byte {
+	return appendDTO([]byte{binaryVersion}, dto)
+}
+
+func appendDTO(data []byte, dto DTO) []byte {
+	data = appendString(data, dto.Name)
+	data = appendString(data, dto.ObjectID)
+
+	names := sortedKeys(dto.Params)
+	data = binary.AppendUvarint(data, uint64(len(names)))
+	for _, name := range names {
+		data = appendString(data, name)
+		data = binary.AppendVarint(data, int64(dto.Params[name]))
+	}
+
+	names = sortedKeys(dto.Args)
+	data = binary.AppendUvarint(data, uint64(len(names)))
+	for _, name := range names {
+		data = appendString(data, name)
+		data = appendString(data, dto.Args[name])
+	}
+
+	data = binary.AppendUvarint(data, uint64(len(dto.Commands)))
+	for _, command := range dto.Commands {
+		data = appendDTO(data, command)
+	}
+
+	return data
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	result := make([]string, 0, len(m))
+	for key := range m {
+		result = append(result, key)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func appendString(data []byte, s string) []byte {
+	data = binary.AppendUvarint(data, uint64(len(s)))
+	return append(data, s...)
+}
+
+func UnmarshalBinaryDTO(data []byte) (DTO, error) {
+	if len(data) == 0 || data[0] != binaryVersion {
+		return DTO{}, fmt.Errorf("%w: unknown binary version", ErrInvalidEncoding)
+	}
+
+	r := &binaryReader{data: data[1:]}
+	dto := r.dto()
+	if r.err == nil && len(r.data) != 0 {
+		r.fail("trailing bytes")
+	}
+	if r.err != nil {
+		return DTO{}, r.err
+	}
+
+	return dto, nil
+}
+
+// binaryReader keeps the first error, so that reads can go on without
+// checking every one.
+type binaryReader struct {
+	data []byte
+	err  error
+}
+
+func (r *binaryReader) fail(reason string) {
+	if r.err == nil {
+		r.err = fmt.Errorf("%w: %s", ErrInvalidEncoding, reason)
+	}
+	r.data = nil
+}
+
+func (r *binaryReader) uvarint() uint64 {
+	value, n := binary.Uvarint(r.data)
+	if n <= 0 {
+		r.fail("bad varint")
+		return 0
+	}
+
+	r.data = r.data[n:]
+	return value
+}
+
+func (r *binaryReader) varint() int64 {
+	value, n := binary.Varint(r.data)
+	if n <= 0 {
+		r.fail("bad varint")
+		return 0
+	}
+
+	r.data = r.data[n:]
+	return value
+}
+
+// count reads the number of the following items, each taking a byte at
+// least.
+func (r *binaryReader) count() int {
+	n := r.uvarint()
+	if n > uint64(len(r.data)) {
+		r.fail("count exceeds data")
+		return 0
+	}
+
+	return int(n)
+}
+
+func (r *binaryReader) string() string {
+	n := r.count()
+	if r.err != nil {
+		return ""
+	}
+
+	result := string(r.data[:n])
+	r.data = r.data[n:]
+	return result
+}
+
+func (r *binaryReader) dto() DTO {
+	result := DTO{
+		Name:     r.string(),
+		ObjectID: r.string(),
+	}
+
+	if n := r.count(); n > 0 {
+		result.Params = make(map[string]int, n)
+		for i := 0; i < n && r.err == nil; i++ {
+			name := r.string()
+			result.Params[name] = int(r.varint())
+		}
+	}
+
+	if n := r.count(); n > 0 {
+		result.Args = make(map[string]string, n)
+		for i := 0; i < n && r.err == nil; i++ {
+			name := r.string()
+			result.Args[name] = r.string()
+		}
+	}
+
+	if n := r.count(); n > 0 {
+		result.Commands = make([]DTO, 0, n)
+		for i := 0; i < n && r.err == nil; i++ {
+			result.Commands = append(result.Commands, r.dto())
+		}
+	}
+
+	return result
+}
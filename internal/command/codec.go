@@ -0,0 +1,641 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"modules/internal/clock"
+	"modules/internal/core"
+	"modules/internal/object"
+)
+
+// DTO is the serializable form of a command: the registered name of its
+// type, the id of the game object it works on, its numeric and string
+// parameters and the DTOs of the commands it is composed of.
+type DTO struct {
+	Name     string            `json:"name"`
+	ObjectID string            `json:"object_id,omitempty"`
+	Params   map[string]int    `json:"params,omitempty"`
+	Args     map[string]string `json:"args,omitempty"`
+	Commands []DTO             `json:"commands,omitempty"`
+}
+
+type EncodeFunc func(c *Codec, command core.Command) (DTO, error)
+
+type DecodeFunc func(c *Codec, dto DTO) (core.Command, error)
+
+type codecEntry struct {
+	encode EncodeFunc
+	decode DecodeFunc
+}
+
+// Codec converts commands to DTOs and back. Decoding resolves the object ids
+// through the object registry of the game and makes the commands work on
+// object adapters, so decorators like a map bounding the movement are not
+// restored.
+type Codec struct {
+	objects *object.Registry
+	removed func(id string) (*object.Object, bool)
+	names   map[reflect.Type]string
+	entries map[string]codecEntry
+}
+
+// NewCodec creates a codec for the game objects that knows MoveCommand,
+// RotateCommand, TurnVelocityCommand, AccelerateCommand, ThrustCommand,
+// DragCommand, CheckFuelCommand, BurnFuelCommand, CheckPermissionCommand,
+// DamageCommand, BounceCommand, MacroCommand, TransactionalMacroCommand and
+// RepeatCommand.
+func NewCodec(objects *object.Registry) *Codec {
+	result := &Codec{
+		objects: objects,
+		names:   map[reflect.Type]string{},
+		entries: map[string]codecEntry{},
+	}
+
+	result.Register("move", &MoveCommand{}, encodeMove, decodeMove)
+	result.Register("rotate", &RotateCommand{}, encodeRotate, decodeRotate)
+	result.Register("turn_velocity", &TurnVelocityCommand{}, encodeTurnVelocity, decodeTurnVelocity)
+	result.Register("accelerate", &AccelerateCommand{}, encodeAccelerate, decodeAccelerate)
+	result.Register("thrust", &ThrustCommand{}, encodeThrust, decodeThrust)
+	result.Register("drag", &DragCommand{}, encodeDrag, decodeDrag)
+	result.Register("check_fuel", &CheckFuelCommand{}, encodeCheckFuel, decodeCheckFuel)
+	result.Register("burn_fuel", &BurnFuelCommand{}, encodeBurnFuel, decodeBurnFuel)
+	result.Register("check_permission", &CheckPermissionCommand{}, encodeCheckPermission, decodeCheckPermission)
+	result.Register("damage", &DamageCommand{}, encodeDamage, decodeDamage)
+	result.Register("bounce", &BounceCommand{}, encodeBounce, decodeBounce)
+	result.Register("macro", &MacroCommand{}, encodeMacro, decodeMacro)
+	result.Register("transactional_macro", &TransactionalMacroCommand{}, encodeTransactionalMacro, decodeTransactionalMacro)
+	result.Register("repeat", RepeatCommand{}, encodeRepeat, decodeRepeat)
+	return result
+}
+
+// Register makes commands of the type of the prototype encoded under the
+// name. Registering a name or a type again replaces it.
+func (c *Codec) Register(name string, prototype core.Command, encode EncodeFunc, decode DecodeFunc) {
+	c.names[reflect.TypeOf(prototype)] = name
+	c.entries[name] = codecEntry{encode: encode, decode: decode}
+}
+
+// SetRemoved makes the codec decode the ids of the objects gone from the
+// registry into the objects lookup finds, e.g. the objects a replay has
+// removed, which the commands journaled later may still work on.
+func (c *Codec) SetRemoved(lookup func(id string) (*object.Object, bool)) {
+	c.removed = lookup
+}
+
+func (c *Codec) Encode(command core.Command) (DTO, error) {
+	name, ok := c.names[reflect.TypeOf(command)]
+	if !ok {
+		return DTO{}, fmt.Errorf("%w: %s", ErrUnknownCommand, TypeName(command))
+	}
+
+	dto, err := c.entries[name].encode(c, command)
+	if err != nil {
+		return DTO{}, fmt.Errorf("%s: %w", name, err)
+	}
+
+	dto.Name = name
+	return dto, nil
+}
+
+func (c *Codec) Decode(dto DTO) (core.Command, error) {
+	entry, ok := c.entries[dto.Name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownCommand, dto.Name)
+	}
+
+	result, err := entry.decode(c, dto)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", dto.Name, err)
+	}
+
+	return result, nil
+}
+
+func (c *Codec) EncodeJSON(command core.Command) ([]byte, error) {
+	dto, err := c.Encode(command)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(dto)
+}
+
+func (c *Codec) DecodeJSON(data []byte) (core.Command, error) {
+	var dto DTO
+	err := json.Unmarshal(data, &dto)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidEncoding, err)
+	}
+
+	return c.Decode(dto)
+}
+
+func (c *Codec) EncodeBinary(command core.Command) ([]byte, error) {
+	dto, err := c.Encode(command)
+	if err != nil {
+		return nil, err
+	}
+
+	return MarshalBinaryDTO(dto), nil
+}
+
+func (c *Codec) DecodeBinary(data []byte) (core.Command, error) {
+	dto, err := UnmarshalBinaryDTO(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Decode(dto)
+}
+
+// ObjectID returns the id of the game object behind the target of a command,
+// e.g. an object adapter, looking through decorators that have an Unwrap
+// method.
+func (c *Codec) ObjectID(target interface{}) (string, error) {
+	for {
+		switch t := target.(type) {
+		case *object.Object:
+			id, ok := c.objects.ID(t)
+			if !ok {
+				// an object removed by the command itself or before it
+				id = t.ID()
+			}
+			if id == "" {
+				return "", ErrUnknownTarget
+			}
+			return id, nil
+		case interface{ Object() core.Object }:
+			target = t.Object()
+		case interface{ Unwrap() core.Movable }:
+			target = t.Unwrap()
+		default:
+			return "", fmt.Errorf("%w: %s", ErrUnknownTarget, TypeName(target))
+		}
+	}
+}
+
+// Object returns an adapter of the game object the DTO refers to.
+func (c *Codec) Object(dto DTO) (*object.Adapter, error) {
+	return c.objectByID(dto.ObjectID)
+}
+
+func (c *Codec) objectByID(id string) (*object.Adapter, error) {
+	o, err := c.objects.Get(id)
+	if err != nil && c.removed != nil {
+		if removed, ok := c.removed(id); ok {
+			o, err = removed, nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return object.NewAdapter(o), nil
+}
+
+func (c *Codec) encodeAll(commands []core.Command) ([]DTO, error) {
+	result := make([]DTO, 0, len(commands))
+	for _, command := range commands {
+		dto, err := c.Encode(command)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, dto)
+	}
+
+	return result, nil
+}
+
+func (c *Codec) decodeAll(dtos []DTO) ([]core.Command, error) {
+	result := make([]core.Command, 0, len(dtos))
+	for _, dto := range dtos {
+		command, err := c.Decode(dto)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, command)
+	}
+
+	return result, nil
+}
+
+func encodeTarget(c *Codec, target interface{}) (DTO, error) {
+	id, err := c.ObjectID(target)
+	if err != nil {
+		return DTO{}, err
+	}
+
+	return DTO{ObjectID: id}, nil
+}
+
+func encodeMove(c *Codec, command core.Command) (DTO, error) {
+	return encodeTarget(c, command.(*MoveCommand).m)
+}
+
+func decodeMove(c *Codec, dto DTO) (core.Command, error) {
+	target, err := c.Object(dto)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewMoveCommand(target), nil
+}
+
+func encodeRotate(c *Codec, command core.Command) (DTO, error) {
+	return encodeTarget(c, command.(*RotateCommand).r)
+}
+
+func decodeRotate(c *Codec, dto DTO) (core.Command, error) {
+	target, err := c.Object(dto)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewRotateCommand(target), nil
+}
+
+func encodeTurnVelocity(c *Codec, command core.Command) (DTO, error) {
+	return encodeTarget(c, command.(*TurnVelocityCommand).object)
+}
+
+func decodeTurnVelocity(c *Codec, dto DTO) (core.Command, error) {
+	target, err := c.Object(dto)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewTurnVelocityCommand(target), nil
+}
+
+func encodeAccelerate(c *Codec, command core.Command) (DTO, error) {
+	return encodeTarget(c, command.(*AccelerateCommand).object)
+}
+
+func decodeAccelerate(c *Codec, dto DTO) (core.Command, error) {
+	target, err := c.Object(dto)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewAccelerateCommand(target), nil
+}
+
+func encodeThrust(c *Codec, command core.Command) (DTO, error) {
+	return encodeTarget(c, command.(*ThrustCommand).object)
+}
+
+func decodeThrust(c *Codec, dto DTO) (core.Command, error) {
+	target, err := c.Object(dto)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewThrustCommand(target), nil
+}
+
+func encodeDrag(c *Codec, command core.Command) (DTO, error) {
+	return encodeTarget(c, command.(*DragCommand).object)
+}
+
+func decodeDrag(c *Codec, dto DTO) (core.Command, error) {
+	target, err := c.Object(dto)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewDragCommand(target), nil
+}
+
+func encodeCheckFuel(c *Codec, command core.Command) (DTO, error) {
+	return encodeTarget(c, command.(*CheckFuelCommand).object)
+}
+
+func decodeCheckFuel(c *Codec, dto DTO) (core.Command, error) {
+	target, err := c.Object(dto)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewCheckFuelCommand(target), nil
+}
+
+func encodeBurnFuel(c *Codec, command core.Command) (DTO, error) {
+	return encodeTarget(c, command.(*BurnFuelCommand).object)
+}
+
+func decodeBurnFuel(c *Codec, dto DTO) (core.Command, error) {
+	target, err := c.Object(dto)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewBurnFuelCommand(target), nil
+}
+
+func encodeCheckPermission(c *Codec, command core.Command) (DTO, error) {
+	check := command.(*CheckPermissionCommand)
+	result, err := encodeTarget(c, check.object)
+	if err != nil {
+		return DTO{}, err
+	}
+
+	dto, err := c.Encode(check.command)
+	if err != nil {
+		return DTO{}, err
+	}
+
+	result.Args = map[string]string{"player_id": check.playerID, "permission": check.permission}
+	result.Commands = []DTO{dto}
+	return result, nil
+}
+
+func decodeCheckPermission(c *Codec, dto DTO) (core.Command, error) {
+	if len(dto.Commands) != 1 {
+		return nil, fmt.Errorf("%w: check_permission needs one command, got %d", ErrInvalidEncoding, len(dto.Commands))
+	}
+
+	target, err := c.Object(dto)
+	if err != nil {
+		return nil, err
+	}
+
+	command, err := c.Decode(dto.Commands[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return NewCheckPermissionCommand(target, dto.Args["player_id"], dto.Args["permission"], command), nil
+}
+
+func encodeDamage(c *Codec, command core.Command) (DTO, error) {
+	damage := command.(*DamageCommand)
+	result, err := encodeTarget(c, damage.target)
+	if err != nil {
+		return DTO{}, err
+	}
+
+	source, err := c.ObjectID(damage.source)
+	if err != nil {
+		return DTO{}, err
+	}
+	result.Args = map[string]string{"source": source}
+
+	if damage.destroy != nil {
+		dto, err := c.Encode(damage.destroy)
+		if err != nil {
+			return DTO{}, err
+		}
+		result.Commands = []DTO{dto}
+	}
+
+	return result, nil
+}
+
+func decodeDamage(c *Codec, dto DTO) (core.Command, error) {
+	if len(dto.Commands) > 1 {
+		return nil, fmt.Errorf("%w: damage takes one destroy command at most, got %d", ErrInvalidEncoding, len(dto.Commands))
+	}
+
+	target, err := c.Object(dto)
+	if err != nil {
+		return nil, err
+	}
+
+	source, err := c.objectByID(dto.Args["source"])
+	if err != nil {
+		return nil, err
+	}
+
+	var destroy core.Command
+	if len(dto.Commands) == 1 {
+		destroy, err = c.Decode(dto.Commands[0])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return NewDamageCommand(target, source, destroy), nil
+}
+
+func encodeBounce(c *Codec, command core.Command) (DTO, error) {
+	bounce := command.(*BounceCommand)
+	result, err := encodeTarget(c, bounce.object)
+	if err != nil {
+		return DTO{}, err
+	}
+
+	obstacle, err := c.ObjectID(bounce.obstacle)
+	if err != nil {
+		return DTO{}, err
+	}
+
+	result.Args = map[string]string{"obstacle": obstacle}
+	return result, nil
+}
+
+func decodeBounce(c *Codec, dto DTO) (core.Command, error) {
+	target, err := c.Object(dto)
+	if err != nil {
+		return nil, err
+	}
+
+	obstacle, err := c.objectByID(dto.Args["obstacle"])
+	if err != nil {
+		return nil, err
+	}
+
+	return NewBounceCommand(target, obstacle), nil
+}
+
+func encodeMacro(c *Codec, command core.Command) (DTO, error) {
+	commands, err := c.encodeAll(command.(*MacroCommand).commands)
+	return DTO{Commands: commands}, err
+}
+
+func decodeMacro(c *Codec, dto DTO) (core.Command, error) {
+	commands, err := c.decodeAll(dto.Commands)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewMacroCommand(commands...), nil
+}
+
+func encodeTransactionalMacro(c *Codec, command core.Command) (DTO, error) {
+	commands, err := c.encodeAll(command.(*TransactionalMacroCommand).commands)
+	return DTO{Commands: commands}, err
+}
+
+func decodeTransactionalMacro(c *Codec, dto DTO) (core.Command, error) {
+	commands, err := c.decodeAll(dto.Commands)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewTransactionalMacroCommand(commands...), nil
+}
+
+func encodeRepeat(c *Codec, command core.Command) (DTO, error) {
+	repeat := command.(RepeatCommand)
+	dto, err := c.Encode(repeat.command)
+	if err != nil {
+		return DTO{}, err
+	}
+
+	return DTO{Params: map[string]int{"attempt": repeat.attempt}, Commands: []DTO{dto}}, nil
+}
+
+func decodeRepeat(c *Codec, dto DTO) (core.Command, error) {
+	if len(dto.Commands) != 1 {
+		return nil, fmt.Errorf("%w: repeat needs one command, got %d", ErrInvalidEncoding, len(dto.Commands))
+	}
+
+	command, err := c.Decode(dto.Commands[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return RepeatCommand{command: command, attempt: dto.Params["attempt"]}, nil
+}
+
+// RegisterRepeating makes the codec know BeginMoveCommand, EndMoveCommand and
+// RepeatingCommand, decoded into commands putting the movements into the
+// queue and keeping their tokens in tokens. A repeating command is encoded
+// with whether its token was cancelled, which makes it skip the command, and
+// with the moved object if it keeps its token in the tokens.
+func RegisterRepeating(codec *Codec, queue core.Queue, tokens *Tokens) {
+	codec.Register("begin_move", &BeginMoveCommand{}, func(c *Codec, command core.Command) (DTO, error) {
+		begin := command.(*BeginMoveCommand)
+		result, err := encodeTarget(c, begin.object)
+		if err != nil {
+			return DTO{}, err
+		}
+
+		dto, err := c.Encode(begin.move)
+		if err != nil {
+			return DTO{}, err
+		}
+
+		result.Commands = []DTO{dto}
+		return result, nil
+	}, func(c *Codec, dto DTO) (core.Command, error) {
+		if len(dto.Commands) != 1 {
+			return nil, fmt.Errorf("%w: begin_move needs one command, got %d", ErrInvalidEncoding, len(dto.Commands))
+		}
+
+		target, err := c.Object(dto)
+		if err != nil {
+			return nil, err
+		}
+
+		move, err := c.Decode(dto.Commands[0])
+		if err != nil {
+			return nil, err
+		}
+
+		return NewBeginMoveCommand(queue, tokens, target.Object(), move), nil
+	})
+
+	codec.Register("end_move", &EndMoveCommand{}, func(c *Codec, command core.Command) (DTO, error) {
+		return encodeTarget(c, command.(*EndMoveCommand).object)
+	}, func(c *Codec, dto DTO) (core.Command, error) {
+		target, err := c.Object(dto)
+		if err != nil {
+			return nil, err
+		}
+
+		return NewEndMoveCommand(tokens, target.Object()), nil
+	})
+
+	codec.Register("repeating", &RepeatingCommand{}, func(c *Codec, command core.Command) (DTO, error) {
+		repeating := command.(*RepeatingCommand)
+		if repeating.stop != nil {
+			return DTO{}, fmt.Errorf("%w: the stop predicate of a repeating command", ErrInvalidEncoding)
+		}
+
+		var result DTO
+		if key, ok := repeating.key.(moveKey); ok && repeating.tokens != nil {
+			var err error
+			result, err = encodeTarget(c, key.object)
+			if err != nil {
+				return DTO{}, err
+			}
+		}
+
+		dto, err := c.Encode(repeating.command)
+		if err != nil {
+			return DTO{}, err
+		}
+
+		result.Commands = []DTO{dto}
+		if repeating.token.IsCancelled() {
+			result.Params = map[string]int{"cancelled": 1}
+		}
+		return result, nil
+	}, func(c *Codec, dto DTO) (core.Command, error) {
+		if len(dto.Commands) != 1 {
+			return nil, fmt.Errorf("%w: repeating needs one command, got %d", ErrInvalidEncoding, len(dto.Commands))
+		}
+
+		command, err := c.Decode(dto.Commands[0])
+		if err != nil {
+			return nil, err
+		}
+
+		token := NewCancellationToken()
+		if dto.Params["cancelled"] != 0 {
+			token.Cancel()
+		}
+		if dto.ObjectID == "" {
+			return NewRepeatingCommand(queue, command, token), nil
+		}
+
+		// A repeating command going on holds the token the tokens keep for
+		// its object.
+		target, err := c.Object(dto)
+		if err != nil {
+			return nil, err
+		}
+		key := moveKey{object: target.Object()}
+		if current := tokens.current(key); current != nil && !token.IsCancelled() {
+			token = current
+		}
+		return NewRepeatingCommand(queue, command, token).WithTokens(tokens, key), nil
+	})
+}
+
+// RegisterShoot makes the codec know ShootCommand. It is encoded with the
+// time of the shot and decoded into a command shooting at that time, with
+// the projectile factory spawn returns for the shooter.
+func RegisterShoot(codec *Codec, spawn func(shooter *object.Adapter) ProjectileFactory) {
+	codec.Register("shoot", &ShootCommand{}, func(c *Codec, command core.Command) (DTO, error) {
+		shoot := command.(*ShootCommand)
+		result, err := encodeTarget(c, shoot.shooter)
+		if err != nil {
+			return DTO{}, err
+		}
+
+		if !shoot.now.IsZero() {
+			result.Args = map[string]string{"time": shoot.now.Format(time.RFC3339Nano)}
+		}
+		return result, nil
+	}, func(c *Codec, dto DTO) (core.Command, error) {
+		shooter, err := c.Object(dto)
+		if err != nil {
+			return nil, err
+		}
+
+		var now time.Time
+		if value, ok := dto.Args["time"]; ok {
+			now, err = time.Parse(time.RFC3339Nano, value)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrInvalidEncoding, err)
+			}
+		}
+
+		return NewShootCommand(shooter, clock.NewFake(now), spawn(shooter)), nil
+	})
+}
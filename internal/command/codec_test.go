@@ -0,0 +1,175 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"modules/internal/core"
+	"modules/internal/mock"
+	"modules/internal/object"
+	"modules/internal/vector"
+)
+
+func TestCodec(t *testing.T) {
+	suite.Run(t, new(CodecTestSuite))
+}
+
+type CodecTestSuite struct {
+	suite.Suite
+
+	objects *object.Registry
+	ship    *object.Object
+	codec   *Codec
+}
+
+func (s *CodecTestSuite) SetupTest() {
+	s.objects = object.NewRegistry()
+	s.ship = object.New(map[string]interface{}{
+		object.Position:         vector.New([]int{12, 5}),
+		object.Velocity:         vector.New([]int{-7, 3}),
+		object.Direction:        1,
+		object.AngularVelocity:  2,
+		object.DirectionsNumber: 8,
+		object.Fuel:             10,
+		object.Consumption:      3,
+	})
+	s.Require().NoError(s.objects.Add("548", s.ship))
+	s.codec = NewCodec(s.objects)
+}
+
+func (s *CodecTestSuite) command() core.Command {
+	ship := object.NewAdapter(s.ship)
+	return RepeatCommand{
+		command: NewMacroCommand(
+			NewMoveWithFuelCommand(ship),
+			NewRotateCommand(ship),
+		),
+		attempt: 2,
+	}
+}
+
+func (s *CodecTestSuite) TestEncode() {
+	dto, err := s.codec.Encode(s.command())
+	s.Require().NoError(err)
+	s.Require().Equal(DTO{
+		Name:   "repeat",
+		Params: map[string]int{"attempt": 2},
+		Commands: []DTO{{
+			Name: "macro",
+			Commands: []DTO{
+				{
+					Name: "transactional_macro",
+					Commands: []DTO{
+						{Name: "check_fuel", ObjectID: "548"},
+						{Name: "move", ObjectID: "548"},
+						{Name: "burn_fuel", ObjectID: "548"},
+					},
+				},
+				{Name: "rotate", ObjectID: "548"},
+			},
+		}},
+	}, dto)
+}
+
+func (s *CodecTestSuite) TestJSON() {
+	data, err := s.codec.EncodeJSON(NewMoveCommand(object.NewAdapter(s.ship)))
+	s.Require().NoError(err)
+	s.Require().JSONEq(`{"name": "move", "object_id": "548"}`, string(data))
+
+	data, err = s.codec.EncodeJSON(s.command())
+	s.Require().NoError(err)
+	s.roundTrip(s.codec.DecodeJSON(data))
+
+	_, err = s.codec.DecodeJSON([]byte(`{"name": 1}`))
+	s.Require().ErrorIs(err, ErrInvalidEncoding)
+}
+
+func (s *CodecTestSuite) TestBinary() {
+	data, err := s.codec.EncodeBinary(NewMoveCommand(object.NewAdapter(s.ship)))
+	s.Require().NoError(err)
+	s.Require().Equal([]byte{1, 4, 'm', 'o', 'v', 'e', 3, '5', '4', '8', 0, 0, 0}, data)
+
+	data, err = s.codec.EncodeBinary(s.command())
+	s.Require().NoError(err)
+	s.roundTrip(s.codec.DecodeBinary(data))
+
+	for _, invalid := range [][]byte{nil, {2}, data[:len(data)-1], append(data, 0), {1, 200}} {
+		_, err = s.codec.DecodeBinary(invalid)
+		s.Require().ErrorIs(err, ErrInvalidEncoding, "%v", invalid)
+	}
+}
+
+// roundTrip checks that the decoded command works on the ship.
+func (s *CodecTestSuite) roundTrip(command core.Command, err error) {
+	s.Require().NoError(err)
+	s.Require().NoError(command.Execute())
+	s.Require().Equal(vector.New([]int{5, 8}), s.ship.Properties()[object.Position])
+	s.Require().Equal(7, s.ship.Properties()[object.Fuel])
+	s.Require().Equal(3, s.ship.Properties()[object.Direction])
+
+	dto, err := s.codec.Encode(command)
+	s.Require().NoError(err)
+	expected, err := s.codec.Encode(s.command())
+	s.Require().NoError(err)
+	s.Require().Equal(expected, dto)
+}
+
+func (s *CodecTestSuite) TestUnknown() {
+	_, err := s.codec.Encode(&mock.CommandMock{})
+	s.Require().ErrorIs(err, ErrUnknownCommand)
+
+	_, err = s.codec.Encode(NewMoveCommand(&mock.MovableMock{}))
+	s.Require().ErrorIs(err, ErrUnknownTarget)
+
+	_, err = s.codec.Encode(NewMoveCommand(object.NewAdapter(object.New(nil))))
+	s.Require().ErrorIs(err, ErrUnknownTarget)
+
+	_, err = s.codec.Decode(DTO{Name: "teleport", ObjectID: "548"})
+	s.Require().ErrorIs(err, ErrUnknownCommand)
+
+	_, err = s.codec.Decode(DTO{Name: "macro", Commands: []DTO{{Name: "move", ObjectID: "549"}}})
+	s.Require().ErrorIs(err, object.ErrUnknownObject)
+
+	_, err = s.codec.Decode(DTO{Name: "repeat"})
+	s.Require().ErrorIs(err, ErrInvalidEncoding)
+}
+
+func (s *CodecTestSuite) TestRemoved() {
+	projectile := object.New(map[string]interface{}{object.Damage: 2})
+	s.objects.AddNew("projectile-", projectile)
+	s.Require().NoError(s.ship.SetProperty(object.Health, 3))
+	damage := NewDamageCommand(object.NewAdapter(s.ship), object.NewAdapter(projectile), nil)
+	s.Require().NoError(s.objects.Remove("projectile-1"))
+
+	dto, err := s.codec.Encode(damage)
+	s.Require().NoError(err)
+	s.Require().Equal(DTO{Name: "damage", ObjectID: "548", Args: map[string]string{"source": "projectile-1"}}, dto)
+
+	_, err = s.codec.Decode(dto)
+	s.Require().ErrorIs(err, object.ErrUnknownObject)
+
+	s.codec.SetRemoved(func(id string) (*object.Object, bool) {
+		return projectile, id == "projectile-1"
+	})
+	decoded, err := s.codec.Decode(dto)
+	s.Require().NoError(err)
+	s.Require().NoError(decoded.Execute())
+	s.Require().Equal(1, s.ship.Properties()[object.Health])
+}
+
+func (s *CodecTestSuite) TestRegister() {
+	s.codec.Register("mock", &mock.CommandMock{}, func(c *Codec, command core.Command) (DTO, error) {
+		return DTO{Params: map[string]int{"n": -1}, Args: map[string]string{"s": "x"}}, nil
+	}, func(c *Codec, dto DTO) (core.Command, error) {
+		result := &mock.CommandMock{}
+		result.On("Execute").Return(nil)
+		return result, nil
+	})
+
+	data, err := s.codec.EncodeBinary(&mock.CommandMock{})
+	s.Require().NoError(err)
+	command, err := s.codec.DecodeBinary(data)
+	s.Require().NoError(err)
+	s.Require().NoError(command.Execute())
+}
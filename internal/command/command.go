@@ -1,6 +1,7 @@
 package command
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"math"
@@ -22,7 +23,7 @@ func StdLogFunc(message string) {
 }
 
 func (c LogCommand) Execute() error {
-	message := fmt.Sprintf("%s got error: '%s'", getType(c.command), c.err)
+	message := fmt.Sprintf("%s got error: '%s'", TypeName(c.command), c.err)
 	c.logFunc(message)
 	return nil
 }
@@ -44,9 +45,13 @@ func NewMoveCommand(m core.Movable) *MoveCommand {
 
 type MoveCommand struct {
 	m core.Movable
+
+	previous vector.Vector
+	executed bool
 }
 
 func (m *MoveCommand) Execute() error {
+	m.executed = false
 	position, err := m.m.GetPosition()
 	if err != nil {
 		return err
@@ -62,6 +67,24 @@ func (m *MoveCommand) Execute() error {
 		return err
 	}
 
+	m.previous = position
+	m.executed = true
+	return nil
+}
+
+// Undo moves the object back to the position it had before the last
+// execution.
+func (m *MoveCommand) Undo() error {
+	if !m.executed {
+		return ErrNotExecuted
+	}
+
+	err := m.m.SetPosition(m.previous)
+	if err != nil {
+		return err
+	}
+
+	m.executed = false
 	return nil
 }
 
@@ -73,9 +96,13 @@ func NewRotateCommand(r core.Rotatable) *RotateCommand {
 
 type RotateCommand struct {
 	r core.Rotatable
+
+	previous int
+	executed bool
 }
 
 func (r *RotateCommand) Execute() error {
+	r.executed = false
 	direction, err := r.r.GetDirection()
 	if err != nil {
 		return err
@@ -97,10 +124,26 @@ func (r *RotateCommand) Execute() error {
 		return err
 	}
 
+	r.previous = direction
+	r.executed = true
+	return nil
+}
+
+func (r *RotateCommand) Undo() error {
+	if !r.executed {
+		return ErrNotExecuted
+	}
+
+	err := r.r.SetDirection(r.previous)
+	if err != nil {
+		return err
+	}
+
+	r.executed = false
 	return nil
 }
 
-func NewTurnVelocityCommand(object core.MovableRotatable) core.Command {
+func NewTurnVelocityCommand(object core.MovableRotatable) *TurnVelocityCommand {
 	return &TurnVelocityCommand{
 		object: object,
 	}
@@ -108,9 +151,13 @@ func NewTurnVelocityCommand(object core.MovableRotatable) core.Command {
 
 type TurnVelocityCommand struct {
 	object core.MovableRotatable
+
+	previous vector.Vector
+	executed bool
 }
 
 func (c *TurnVelocityCommand) Execute() error {
+	c.executed = false
 	velocity, err := c.object.GetVelocity()
 	if err != nil {
 		return err
@@ -136,20 +183,30 @@ func (c *TurnVelocityCommand) Execute() error {
 	}
 
 	directionNew := (direction + angularVelocity) % n
-	alpha := float64(directionNew) / float64(n) * 2 * math.Pi
-
 	vx, vy := velocity[0], velocity[1]
-	v := math.Sqrt(float64(vx*vx) + float64(vy*vy))
+	turned := directionVector(math.Sqrt(float64(vx*vx)+float64(vy*vy)), directionNew, n)
+
+	err = c.object.SetVelocity(turned)
+	if err != nil {
+		return err
+	}
 
-	vx = int(v * math.Cos(alpha))
-	vy = int(v * math.Sin(alpha))
-	velocity = vector.New([]int{vx, vy})
+	c.previous = velocity
+	c.executed = true
+	return nil
+}
+
+func (c *TurnVelocityCommand) Undo() error {
+	if !c.executed {
+		return ErrNotExecuted
+	}
 
-	err = c.object.SetVelocity(velocity)
+	err := c.object.SetVelocity(c.previous)
 	if err != nil {
 		return err
 	}
 
+	c.executed = false
 	return nil
 }
 
@@ -186,6 +243,8 @@ func (c *CheckFuelCommand) Execute() (err error) {
 
 type BurnFuelCommand struct {
 	*CheckFuelCommand
+
+	executed bool
 }
 
 func NewBurnFuelCommand(object core.FuelBurnable) *BurnFuelCommand {
@@ -194,7 +253,8 @@ func NewBurnFuelCommand(object core.FuelBurnable) *BurnFuelCommand {
 	}
 }
 
-func (c BurnFuelCommand) Execute() error {
+func (c *BurnFuelCommand) Execute() error {
+	c.executed = false
 	err := c.CheckFuelCommand.Execute()
 	if err != nil {
 		return err
@@ -205,6 +265,22 @@ func (c BurnFuelCommand) Execute() error {
 		return err
 	}
 
+	c.executed = true
+	return nil
+}
+
+// Undo gives back the fuel burnt by the last execution.
+func (c *BurnFuelCommand) Undo() error {
+	if !c.executed {
+		return ErrNotExecuted
+	}
+
+	err := c.object.SetFuel(c.fuel)
+	if err != nil {
+		return err
+	}
+
+	c.executed = false
 	return nil
 }
 
@@ -218,22 +294,98 @@ func NewMacroCommand(commands ...core.Command) core.Command {
 	return result
 }
 
-func (c MacroCommand) Execute() (err error) {
-	for _, command := range c.commands {
-		err = command.Execute()
+// Execute stops at the first failed command and returns its error as a
+// StepError.
+func (c *MacroCommand) Execute() error {
+	for i, command := range c.commands {
+		err := command.Execute()
+		if err != nil {
+			return &StepError{Macro: c, Command: command, Index: i, Err: err}
+		}
+	}
+
+	return nil
+}
+
+// from returns a macro of the commands starting at the index.
+func (c *MacroCommand) from(index int) *MacroCommand {
+	result := &MacroCommand{}
+	result.commands = append(result.commands, c.commands[index:]...)
+	return result
+}
+
+// TransactionalMacroCommand executes the commands in order like
+// MacroCommand. When a command fails, it undoes the commands executed before
+// in reverse order, so the commands take effect either all or none. Commands
+// that are not core.Undoable, e.g. CheckFuelCommand, are taken to have no
+// effects to undo.
+type TransactionalMacroCommand struct {
+	commands []core.Command
+	executed int
+	done     bool
+}
+
+func NewTransactionalMacroCommand(commands ...core.Command) *TransactionalMacroCommand {
+	result := &TransactionalMacroCommand{}
+	result.commands = append(result.commands, commands...)
+	return result
+}
+
+// Execute returns the error of the failed command as a StepError. It is
+// joined with the errors of the undone commands if the rollback fails.
+func (c *TransactionalMacroCommand) Execute() error {
+	c.executed = 0
+	c.done = false
+	for i, command := range c.commands {
+		err := command.Execute()
 		if err != nil {
+			err = &StepError{Macro: c, Command: command, Index: i, Err: err}
+			if rollbackErr := c.rollback(); rollbackErr != nil {
+				return errors.Join(err, rollbackErr)
+			}
 			return err
 		}
+		c.executed++
 	}
 
+	c.done = true
 	return nil
 }
 
+// Undo undoes all the commands in reverse order.
+func (c *TransactionalMacroCommand) Undo() error {
+	if !c.done {
+		return ErrNotExecuted
+	}
+
+	c.done = false
+	return c.rollback()
+}
+
+// rollback undoes the executed commands. It goes on past a failed undo to
+// restore as much as it can.
+func (c *TransactionalMacroCommand) rollback() error {
+	var errs []error
+	for ; c.executed > 0; c.executed-- {
+		undoable, ok := c.commands[c.executed-1].(core.Undoable)
+		if !ok {
+			continue
+		}
+
+		err := undoable.Undo()
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
 func NewMoveWithFuelCommand(object core.MovableWithFuel) core.Command {
 	checkCommand := NewCheckFuelCommand(object)
 	moveCommand := NewMoveCommand(object)
 	burnCommand := NewBurnFuelCommand(object)
-	result := NewMacroCommand(checkCommand, moveCommand, burnCommand)
+	result := NewTransactionalMacroCommand(checkCommand, moveCommand, burnCommand)
 	return result
 }
 
@@ -243,8 +395,173 @@ func NewRotateWithVelocityCommand(object core.Rotatable) core.Command {
 	movableRotatable, isMovableRotatable := object.(core.MovableRotatable)
 	if isMovableRotatable {
 		turnCommand := NewTurnVelocityCommand(movableRotatable)
-		return NewMacroCommand(rotateCommand, turnCommand)
+		return NewTransactionalMacroCommand(rotateCommand, turnCommand)
 	}
 
 	return rotateCommand
 }
+
+// CheckPermissionCommand executes the command only if the player owns the
+// object or the object ACL grants the player the permission.
+type CheckPermissionCommand struct {
+	object     core.Controllable
+	playerID   string
+	permission string
+	command    core.Command
+}
+
+func NewCheckPermissionCommand(object core.Controllable, playerID string, permission string, command core.Command) *CheckPermissionCommand {
+	return &CheckPermissionCommand{
+		object:     object,
+		playerID:   playerID,
+		permission: permission,
+		command:    command,
+	}
+}
+
+func (c *CheckPermissionCommand) Execute() error {
+	err := c.Check()
+	if err != nil {
+		return err
+	}
+
+	return c.command.Execute()
+}
+
+// Check fails with a PermissionError if the player may not execute the
+// command, without executing it.
+func (c *CheckPermissionCommand) Check() error {
+	allowed, err := c.allowed()
+	if err != nil {
+		return err
+	}
+
+	if !allowed {
+		return &PermissionError{
+			PlayerID:   c.playerID,
+			Permission: c.permission,
+		}
+	}
+
+	return nil
+}
+
+func (c *CheckPermissionCommand) allowed() (bool, error) {
+	owner, err := c.object.GetOwner()
+	if err != nil {
+		return false, err
+	}
+
+	if owner != "" && owner == c.playerID {
+		return true, nil
+	}
+
+	acl, err := c.object.GetACL()
+	if err != nil {
+		return false, err
+	}
+
+	for _, permission := range acl[c.playerID] {
+		if permission == c.permission {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// DamageCommand takes the damage of the source from the health of the
+// target. It executes the destroy command, if any, once the health is gone.
+type DamageCommand struct {
+	target  core.Damageable
+	source  core.Damaging
+	destroy core.Command
+}
+
+func NewDamageCommand(target core.Damageable, source core.Damaging, destroy core.Command) *DamageCommand {
+	return &DamageCommand{
+		target:  target,
+		source:  source,
+		destroy: destroy,
+	}
+}
+
+func (c *DamageCommand) Execute() error {
+	damage, err := c.source.GetDamage()
+	if err != nil {
+		return err
+	}
+
+	health, err := c.target.GetHealth()
+	if err != nil {
+		return err
+	}
+
+	health -= damage
+	err = c.target.SetHealth(health)
+	if err != nil {
+		return err
+	}
+
+	if health <= 0 && c.destroy != nil {
+		return c.destroy.Execute()
+	}
+
+	return nil
+}
+
+// BounceCommand reflects the velocity of the object off the obstacle it
+// overlaps, along the line between their positions. An object already
+// moving away from the obstacle keeps its velocity, so does one at the very
+// position of the obstacle.
+type BounceCommand struct {
+	object   core.MovableAccelerating
+	obstacle core.Movable
+}
+
+func NewBounceCommand(object core.MovableAccelerating, obstacle core.Movable) *BounceCommand {
+	return &BounceCommand{
+		object:   object,
+		obstacle: obstacle,
+	}
+}
+
+func (c *BounceCommand) Execute() error {
+	position, err := c.object.GetPosition()
+	if err != nil {
+		return err
+	}
+
+	velocity, err := c.object.GetVelocity()
+	if err != nil {
+		return err
+	}
+
+	obstacle, err := c.obstacle.GetPosition()
+	if err != nil {
+		return err
+	}
+
+	if len(position) != len(velocity) || len(position) != len(obstacle) {
+		return ErrUnsupportedDimension
+	}
+
+	var dot, norm float64
+	normal := make([]float64, len(position))
+	for i := range position {
+		normal[i] = float64(position[i] - obstacle[i])
+		dot += float64(velocity[i]) * normal[i]
+		norm += normal[i] * normal[i]
+	}
+
+	if norm == 0 || dot >= 0 {
+		return nil
+	}
+
+	reflected := make([]int, len(velocity))
+	for i := range velocity {
+		reflected[i] = int(math.Round(float64(velocity[i]) - 2*dot/norm*normal[i]))
+	}
+
+	return c.object.SetVelocity(vector.New(reflected))
+}
@@ -11,7 +11,8 @@ import (
 )
 
 var (
-	errSomeError = fmt.Errorf("some error")
+	errSomeError      = fmt.Errorf("some error")
+	errSomeOtherError = fmt.Errorf("some other error")
 )
 
 func TestCommand(t *testing.T) {
@@ -108,6 +109,21 @@ func (s *MoveTestSuite) TestSetPosError() {
 	s.Require().Error(err)
 }
 
+func (s *MoveTestSuite) TestUndo() {
+	pos := vector.New([]int{12, 5})
+	v := vector.New([]int{-7, 3})
+	posNew := vector.New([]int{5, 8})
+	s.mock.On("GetPosition").Return(pos, nil).
+		On("GetVelocity").Return(v, nil).
+		On("SetPosition", posNew).Return(nil).
+		On("SetPosition", pos).Return(nil)
+	move := NewMoveCommand(&s.mock)
+	s.Require().ErrorIs(move.Undo(), ErrNotExecuted)
+	s.Require().NoError(move.Execute())
+	s.Require().NoError(move.Undo())
+	s.Require().ErrorIs(move.Undo(), ErrNotExecuted)
+}
+
 func TestRotate(t *testing.T) {
 	suite.Run(t, new(RotateTestSuite))
 }
@@ -136,6 +152,18 @@ func (s *RotateTestSuite) TestSuccess() {
 	s.Require().NoError(err)
 }
 
+func (s *RotateTestSuite) TestUndo() {
+	s.mock.On("GetDirection").Return(300, nil).
+		On("GetAngularVelocity").Return(70, nil).
+		On("GetDirectionsNumber").Return(360, nil).
+		On("SetDirection", 10).Return(nil).
+		On("SetDirection", 300).Return(nil)
+	rotate := NewRotateCommand(&s.mock)
+	s.Require().NoError(rotate.Execute())
+	s.Require().NoError(rotate.Undo())
+	s.Require().ErrorIs(rotate.Undo(), ErrNotExecuted)
+}
+
 func (s *RotateTestSuite) TestDirectionError() {
 	s.mock.On("GetDirection").Return(0, errSomeError)
 	rotate := NewRotateCommand(&s.mock)
@@ -249,6 +277,17 @@ func (s *BurnFuelTestSuite) TestSuccess() {
 	s.Require().NoError(err)
 }
 
+func (s *BurnFuelTestSuite) TestUndo() {
+	s.mock.On("GetFuel").Return(300, nil).
+		On("GetConsumption").Return(70, nil).
+		On("SetFuel", 230).Return(nil).
+		On("SetFuel", 300).Return(nil)
+	command := NewBurnFuelCommand(&s.mock)
+	s.Require().NoError(command.Execute())
+	s.Require().NoError(command.Undo())
+	s.Require().ErrorIs(command.Undo(), ErrNotExecuted)
+}
+
 func (s *BurnFuelTestSuite) TestGetFuelError() {
 	s.mock.On("GetFuel").Return(0, errSomeError)
 	command := NewBurnFuelCommand(&s.mock)
@@ -331,6 +370,95 @@ func (s *MacroCommandTestSuite) TestError2() {
 	s.Require().ErrorIs(err, errSomeError)
 }
 
+func (s *MacroCommandTestSuite) TestStepError() {
+	s.mock1.On("Execute").Return(nil)
+	s.mock2.On("Execute").Return(&PermissionError{PlayerID: "bob", Permission: "fire"})
+	command := NewMacroCommand(&s.mock1, &s.mock2)
+	err := command.Execute()
+
+	var stepErr *StepError
+	s.Require().ErrorAs(err, &stepErr)
+	s.Require().Same(command, stepErr.Macro)
+	s.Require().Same(&s.mock2, stepErr.Command)
+	s.Require().Equal(1, stepErr.Index)
+	s.Require().ErrorIs(err, ErrPermissionDenied)
+
+	var permissionError *PermissionError
+	s.Require().ErrorAs(err, &permissionError)
+	s.Require().Equal(`step 1 (CommandMock): permission denied: player "bob" may not fire the object`, err.Error())
+}
+
+func TestTransactionalMacro(t *testing.T) {
+	suite.Run(t, new(TransactionalMacroTestSuite))
+}
+
+type TransactionalMacroTestSuite struct {
+	suite.Suite
+
+	check mock.CommandMock
+	first mock.UndoableMock
+	last  mock.UndoableMock
+	undos []string
+}
+
+func (s *TransactionalMacroTestSuite) SetupTest() {
+	s.check = mock.CommandMock{}
+	s.first = mock.UndoableMock{}
+	s.last = mock.UndoableMock{}
+	s.undos = nil
+}
+
+func (s *TransactionalMacroTestSuite) TearDownTest() {
+	s.check.AssertExpectations(s.T())
+	s.first.AssertExpectations(s.T())
+	s.last.AssertExpectations(s.T())
+}
+
+func (s *TransactionalMacroTestSuite) undo(name string, err error) func() error {
+	return func() error {
+		s.undos = append(s.undos, name)
+		return err
+	}
+}
+
+func (s *TransactionalMacroTestSuite) TestSuccess() {
+	s.check.On("Execute").Return(nil)
+	s.first.On("Execute").Return(nil).
+		On("Undo").Return(s.undo("first", nil))
+	s.last.On("Execute").Return(nil).
+		On("Undo").Return(s.undo("last", nil))
+	command := NewTransactionalMacroCommand(&s.check, &s.first, &s.last)
+	s.Require().ErrorIs(command.Undo(), ErrNotExecuted)
+	s.Require().NoError(command.Execute())
+	s.Require().NoError(command.Undo())
+	s.Require().Equal([]string{"last", "first"}, s.undos)
+	s.Require().ErrorIs(command.Undo(), ErrNotExecuted)
+}
+
+func (s *TransactionalMacroTestSuite) TestRollback() {
+	s.check.On("Execute").Return(nil)
+	s.first.On("Execute").Return(nil).
+		On("Undo").Return(s.undo("first", nil))
+	s.last.On("Execute").Return(errSomeError)
+	command := NewTransactionalMacroCommand(&s.first, &s.check, &s.last)
+	err := command.Execute()
+	s.Require().Equal(&StepError{Macro: command, Command: &s.last, Index: 2, Err: errSomeError}, err)
+	s.Require().Equal([]string{"first"}, s.undos)
+	s.Require().ErrorIs(command.Undo(), ErrNotExecuted)
+}
+
+func (s *TransactionalMacroTestSuite) TestRollbackError() {
+	s.first.On("Execute").Return(nil).
+		On("Undo").Return(s.undo("first", errSomeOtherError))
+	s.last.On("Execute").Return(nil).
+		On("Undo").Return(s.undo("last", nil))
+	s.check.On("Execute").Return(errSomeError)
+	err := NewTransactionalMacroCommand(&s.first, &s.last, &s.check).Execute()
+	s.Require().ErrorIs(err, errSomeError)
+	s.Require().ErrorIs(err, errSomeOtherError)
+	s.Require().Equal([]string{"last", "first"}, s.undos)
+}
+
 func TestMoveWithFuel(t *testing.T) {
 	suite.Run(t, new(MoveWithFuelTestSuite))
 }
@@ -391,7 +519,8 @@ func (s *MoveWithFuelTestSuite) TestMoveError() {
 func (s *MoveWithFuelTestSuite) TestBurnError() {
 	s.mock.MovableMock.On("GetPosition").Return(s.position, nil).
 		On("GetVelocity").Return(s.velocity, nil).
-		On("SetPosition", s.positionNew).Return(nil)
+		On("SetPosition", s.positionNew).Return(nil).
+		On("SetPosition", s.position).Return(nil)
 	s.mock.FuelBurnableMock.On("GetFuel").Return(300, nil).
 		On("GetConsumption").Return(70, nil).
 		On("SetFuel", 230).Return(errSomeError)
@@ -399,6 +528,20 @@ func (s *MoveWithFuelTestSuite) TestBurnError() {
 	err := command.Execute()
 	s.Require().Error(err)
 	s.Require().ErrorIs(err, errSomeError)
+	s.mock.MovableMock.AssertNumberOfCalls(s.T(), "SetPosition", 2)
+}
+
+func (s *MoveWithFuelTestSuite) TestRollbackError() {
+	s.mock.MovableMock.On("GetPosition").Return(s.position, nil).
+		On("GetVelocity").Return(s.velocity, nil).
+		On("SetPosition", s.positionNew).Return(nil).
+		On("SetPosition", s.position).Return(errSomeOtherError)
+	s.mock.FuelBurnableMock.On("GetFuel").Return(300, nil).
+		On("GetConsumption").Return(70, nil).
+		On("SetFuel", 230).Return(errSomeError)
+	err := NewMoveWithFuelCommand(&s.mock).Execute()
+	s.Require().ErrorIs(err, errSomeError)
+	s.Require().ErrorIs(err, errSomeOtherError)
 }
 
 func TestTurnVelocity(t *testing.T) {
@@ -445,6 +588,19 @@ func (s *TurnVelocityTestSuite) TestSuccess() {
 	s.Require().NoError(err)
 }
 
+func (s *TurnVelocityTestSuite) TestUndo() {
+	s.mock.MovableMock.On("GetVelocity").Return(s.velocity, nil)
+	s.mock.RotatableMock.On("GetDirection").Return(s.direction, nil).
+		On("GetAngularVelocity").Return(s.angularVelocity, nil).
+		On("GetDirectionsNumber").Return(s.directionsNumber, nil)
+	s.mock.AcceleratingMock.On("SetVelocity", s.velocityNew).Return(nil).
+		On("SetVelocity", s.velocity).Return(nil)
+	command := NewTurnVelocityCommand(&s.mock)
+	s.Require().NoError(command.Execute())
+	s.Require().NoError(command.Undo())
+	s.Require().ErrorIs(command.Undo(), ErrNotExecuted)
+}
+
 func (s *TurnVelocityTestSuite) TestGetVelocityError() {
 	s.mock.MovableMock.On("GetVelocity").Return(s.nilVector, errSomeError)
 	command := NewTurnVelocityCommand(&s.mock)
@@ -535,3 +691,269 @@ func (s *RotateWithVelocityTestSuite) TestSuccessOnlyRotate() {
 	err := command.Execute()
 	s.Require().NoError(err)
 }
+
+func TestCheckPermission(t *testing.T) {
+	suite.Run(t, new(CheckPermissionTestSuite))
+}
+
+type CheckPermissionTestSuite struct {
+	suite.Suite
+
+	object  mock.ControllableMock
+	command mock.CommandMock
+}
+
+func (s *CheckPermissionTestSuite) SetupTest() {
+	s.object = mock.ControllableMock{}
+	s.command = mock.CommandMock{}
+}
+
+func (s *CheckPermissionTestSuite) TearDownTest() {
+	s.object.AssertExpectations(s.T())
+	s.command.AssertExpectations(s.T())
+}
+
+func (s *CheckPermissionTestSuite) TestOwner() {
+	s.object.On("GetOwner").Return("alice", nil)
+	s.command.On("Execute").Return(nil)
+	err := NewCheckPermissionCommand(&s.object, "alice", "fire", &s.command).Execute()
+	s.Require().NoError(err)
+}
+
+func (s *CheckPermissionTestSuite) TestACL() {
+	s.object.On("GetOwner").Return("alice", nil).
+		On("GetACL").Return(map[string][]string{"bob": {"move", "rotate"}}, nil)
+	s.command.On("Execute").Return(nil)
+	err := NewCheckPermissionCommand(&s.object, "bob", "rotate", &s.command).Execute()
+	s.Require().NoError(err)
+}
+
+func (s *CheckPermissionTestSuite) TestDenied() {
+	s.object.On("GetOwner").Return("alice", nil).
+		On("GetACL").Return(map[string][]string{"bob": {"move"}}, nil)
+	err := NewCheckPermissionCommand(&s.object, "bob", "fire", &s.command).Execute()
+	s.Require().ErrorIs(err, ErrPermissionDenied)
+
+	var permissionError *PermissionError
+	s.Require().ErrorAs(err, &permissionError)
+	s.Require().Equal(PermissionError{PlayerID: "bob", Permission: "fire"}, *permissionError)
+}
+
+func (s *CheckPermissionTestSuite) TestNoOwner() {
+	s.object.On("GetOwner").Return("", nil).
+		On("GetACL").Return(nil, nil)
+	err := NewCheckPermissionCommand(&s.object, "", "move", &s.command).Execute()
+	s.Require().ErrorIs(err, ErrPermissionDenied)
+}
+
+func (s *CheckPermissionTestSuite) TestGetOwnerError() {
+	s.object.On("GetOwner").Return("", errSomeError)
+	err := NewCheckPermissionCommand(&s.object, "bob", "move", &s.command).Execute()
+	s.Require().ErrorIs(err, errSomeError)
+}
+
+func (s *CheckPermissionTestSuite) TestGetACLError() {
+	s.object.On("GetOwner").Return("alice", nil).
+		On("GetACL").Return(nil, errSomeError)
+	err := NewCheckPermissionCommand(&s.object, "bob", "move", &s.command).Execute()
+	s.Require().ErrorIs(err, errSomeError)
+}
+
+func TestDamage(t *testing.T) {
+	suite.Run(t, new(DamageTestSuite))
+}
+
+type DamageTestSuite struct {
+	suite.Suite
+
+	target  mock.DamageableMock
+	source  mock.DamageableMock
+	destroy mock.CommandMock
+}
+
+func (s *DamageTestSuite) SetupTest() {
+	s.target = mock.DamageableMock{}
+	s.source = mock.DamageableMock{}
+	s.destroy = mock.CommandMock{}
+}
+
+func (s *DamageTestSuite) TearDownTest() {
+	s.target.AssertExpectations(s.T())
+	s.source.AssertExpectations(s.T())
+	s.destroy.AssertExpectations(s.T())
+}
+
+func (s *DamageTestSuite) TestSuccess() {
+	s.source.On("GetDamage").Return(2, nil)
+	s.target.On("GetHealth").Return(5, nil).
+		On("SetHealth", 3).Return(nil)
+	err := NewDamageCommand(&s.target, &s.source, &s.destroy).Execute()
+	s.Require().NoError(err)
+}
+
+func (s *DamageTestSuite) TestDestroy() {
+	s.source.On("GetDamage").Return(2, nil)
+	s.target.On("GetHealth").Return(1, nil).
+		On("SetHealth", -1).Return(nil)
+	s.destroy.On("Execute").Return(errSomeError)
+	err := NewDamageCommand(&s.target, &s.source, &s.destroy).Execute()
+	s.Require().ErrorIs(err, errSomeError)
+}
+
+func (s *DamageTestSuite) TestGetHealthError() {
+	s.source.On("GetDamage").Return(2, nil)
+	s.target.On("GetHealth").Return(0, errSomeError)
+	err := NewDamageCommand(&s.target, &s.source, nil).Execute()
+	s.Require().ErrorIs(err, errSomeError)
+}
+
+func TestBounce(t *testing.T) {
+	suite.Run(t, new(BounceTestSuite))
+}
+
+type BounceTestSuite struct {
+	suite.Suite
+
+	object   mock.MovableAcceleratingMock
+	obstacle mock.MovableMock
+}
+
+func (s *BounceTestSuite) SetupTest() {
+	s.object = mock.MovableAcceleratingMock{}
+	s.obstacle = mock.MovableMock{}
+	s.object.MovableMock.On("GetPosition").Return(vector.New([]int{0, 0}), nil)
+}
+
+func (s *BounceTestSuite) TearDownTest() {
+	s.object.MovableMock.AssertExpectations(s.T())
+	s.object.AcceleratingMock.AssertExpectations(s.T())
+	s.obstacle.AssertExpectations(s.T())
+}
+
+func (s *BounceTestSuite) TestReflect() {
+	s.object.MovableMock.On("GetVelocity").Return(vector.New([]int{3, 4}), nil)
+	s.obstacle.On("GetPosition").Return(vector.New([]int{2, 0}), nil)
+	s.object.AcceleratingMock.On("SetVelocity", vector.New([]int{-3, 4})).Return(nil)
+	err := NewBounceCommand(&s.object, &s.obstacle).Execute()
+	s.Require().NoError(err)
+}
+
+func (s *BounceTestSuite) TestMovingAway() {
+	s.object.MovableMock.On("GetVelocity").Return(vector.New([]int{-3, 4}), nil)
+	s.obstacle.On("GetPosition").Return(vector.New([]int{2, 0}), nil)
+	err := NewBounceCommand(&s.object, &s.obstacle).Execute()
+	s.Require().NoError(err)
+}
+
+func (s *BounceTestSuite) TestDimensions() {
+	s.object.MovableMock.On("GetVelocity").Return(vector.New([]int{3, 4}), nil)
+	s.obstacle.On("GetPosition").Return(vector.New([]int{2, 0, 1}), nil)
+	err := NewBounceCommand(&s.object, &s.obstacle).Execute()
+	s.Require().ErrorIs(err, ErrUnsupportedDimension)
+}
+
+func TestAccelerate(t *testing.T) {
+	suite.Run(t, new(AccelerateTestSuite))
+}
+
+type AccelerateTestSuite struct {
+	suite.Suite
+
+	mock mock.ThrustableMock
+}
+
+func (s *AccelerateTestSuite) SetupTest() {
+	s.mock = mock.ThrustableMock{}
+	s.mock.MovableMock.On("GetVelocity").Return(vector.New([]int{10, 0}), nil)
+	s.mock.RotatableMock.On("GetDirection").Return(90, nil).
+		On("GetDirectionsNumber").Return(360, nil)
+}
+
+func (s *AccelerateTestSuite) TearDownTest() {
+	s.mock.MovableMock.AssertExpectations(s.T())
+	s.mock.AcceleratingMock.AssertExpectations(s.T())
+	s.mock.ThrustingMock.AssertExpectations(s.T())
+	s.mock.FuelBurnableMock.AssertExpectations(s.T())
+}
+
+func (s *AccelerateTestSuite) TestSuccess() {
+	s.mock.ThrustingMock.On("GetThrust").Return(5, nil).
+		On("GetMaxSpeed").Return(0, nil)
+	s.mock.AcceleratingMock.On("SetVelocity", vector.New([]int{10, 5})).Return(nil)
+	err := NewAccelerateCommand(&s.mock).Execute()
+	s.Require().NoError(err)
+}
+
+func (s *AccelerateTestSuite) TestMaxSpeed() {
+	s.mock.ThrustingMock.On("GetThrust").Return(10, nil).
+		On("GetMaxSpeed").Return(10, nil)
+	s.mock.AcceleratingMock.On("SetVelocity", vector.New([]int{7, 7})).Return(nil)
+	err := NewAccelerateCommand(&s.mock).Execute()
+	s.Require().NoError(err)
+}
+
+func (s *AccelerateTestSuite) TestGetThrustError() {
+	s.mock.ThrustingMock.On("GetThrust").Return(0, errSomeError)
+	err := NewAccelerateCommand(&s.mock).Execute()
+	s.Require().ErrorIs(err, errSomeError)
+}
+
+func (s *AccelerateTestSuite) TestThrust() {
+	s.mock.ThrustingMock.On("GetThrust").Return(-5, nil).
+		On("GetMaxSpeed").Return(0, nil).
+		On("GetThrustConsumption").Return(2, nil)
+	s.mock.FuelBurnableMock.On("GetFuel").Return(15, nil).
+		On("SetFuel", 5).Return(nil)
+	s.mock.AcceleratingMock.On("SetVelocity", vector.New([]int{10, -5})).Return(nil)
+	err := NewThrustCommand(&s.mock).Execute()
+	s.Require().NoError(err)
+}
+
+func (s *AccelerateTestSuite) TestThrustNotEnoughFuel() {
+	s.mock = mock.ThrustableMock{}
+	s.mock.ThrustingMock.On("GetThrust").Return(5, nil).
+		On("GetThrustConsumption").Return(2, nil)
+	s.mock.FuelBurnableMock.On("GetFuel").Return(9, nil)
+	err := NewThrustCommand(&s.mock).Execute()
+	s.Require().ErrorIs(err, ErrNotEnoughFuel)
+}
+
+func TestDrag(t *testing.T) {
+	suite.Run(t, new(DragTestSuite))
+}
+
+type DragTestSuite struct {
+	suite.Suite
+
+	mock mock.ThrustableMock
+}
+
+func (s *DragTestSuite) SetupTest() {
+	s.mock = mock.ThrustableMock{}
+}
+
+func (s *DragTestSuite) TearDownTest() {
+	s.mock.MovableMock.AssertExpectations(s.T())
+	s.mock.AcceleratingMock.AssertExpectations(s.T())
+	s.mock.ThrustingMock.AssertExpectations(s.T())
+}
+
+func (s *DragTestSuite) TestSuccess() {
+	s.mock.ThrustingMock.On("GetDrag").Return(10, nil)
+	s.mock.MovableMock.On("GetVelocity").Return(vector.New([]int{100, -25}), nil)
+	s.mock.AcceleratingMock.On("SetVelocity", vector.New([]int{90, -22})).Return(nil)
+	err := NewDragCommand(&s.mock).Execute()
+	s.Require().NoError(err)
+}
+
+func (s *DragTestSuite) TestNoDrag() {
+	s.mock.ThrustingMock.On("GetDrag").Return(0, nil)
+	err := NewDragCommand(&s.mock).Execute()
+	s.Require().NoError(err)
+}
+
+func (s *DragTestSuite) TestGetDragError() {
+	s.mock.ThrustingMock.On("GetDrag").Return(0, errSomeError)
+	err := NewDragCommand(&s.mock).Execute()
+	s.Require().ErrorIs(err, errSomeError)
+}
@@ -0,0 +1,145 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"modules/internal/core"
+	"modules/internal/ioc"
+	"modules/internal/object"
+)
+
+// ParallelCommand executes independent commands concurrently and waits for
+// all of them. The commands must not change the same objects. They run in
+// the IoC scope of the goroutine executing the ParallelCommand.
+type ParallelCommand struct {
+	commands []core.Command
+}
+
+func NewParallelCommand(commands ...core.Command) *ParallelCommand {
+	result := &ParallelCommand{}
+	result.commands = append(result.commands, commands...)
+	return result
+}
+
+// Execute returns the errors of the failed commands joined in the order of
+// the commands.
+func (c *ParallelCommand) Execute() error {
+	inherit := ioc.Resolve("Scopes.Inherit").(core.Command)
+	errs := make([]error, len(c.commands))
+	var wg sync.WaitGroup
+	for i, command := range c.commands {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer ioc.Resolve("Scopes.Release").(core.Command).Execute()
+			errs[i] = inherit.Execute()
+			if errs[i] == nil {
+				errs[i] = command.Execute()
+			}
+		}()
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// IfCommand executes the command only if the condition command succeeds,
+// e.g. a CheckFuelCommand. Otherwise it executes the else command, if any.
+// The error of the condition is not an error of the IfCommand.
+type IfCommand struct {
+	condition core.Command
+	then      core.Command
+	otherwise core.Command
+}
+
+func NewIfCommand(condition core.Command, then core.Command) *IfCommand {
+	return &IfCommand{
+		condition: condition,
+		then:      then,
+	}
+}
+
+func (c *IfCommand) WithElse(otherwise core.Command) *IfCommand {
+	c.otherwise = otherwise
+	return c
+}
+
+func (c *IfCommand) Execute() error {
+	if c.condition.Execute() == nil {
+		return c.then.Execute()
+	}
+
+	if c.otherwise != nil {
+		return c.otherwise.Execute()
+	}
+
+	return nil
+}
+
+// FallbackCommand executes the alternatives in order until one of them
+// succeeds. It fails with the errors of all the alternatives joined if none
+// does.
+type FallbackCommand struct {
+	alternatives []core.Command
+}
+
+func NewFallbackCommand(alternatives ...core.Command) *FallbackCommand {
+	result := &FallbackCommand{}
+	result.alternatives = append(result.alternatives, alternatives...)
+	return result
+}
+
+func (c *FallbackCommand) Execute() error {
+	var errs []error
+	for _, alternative := range c.alternatives {
+		err := alternative.Execute()
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+// ForEachCommand executes a command created for every game object, e.g. an
+// operation resolved from "Operations.<operation_id>". The objects are the
+// ones present on execution, and a nil command skips the object. A failed
+// command does not stop the others.
+type ForEachCommand struct {
+	objects *object.Registry
+	create  func(target core.Object) core.Command
+}
+
+func NewForEachCommand(objects *object.Registry, create func(target core.Object) core.Command) *ForEachCommand {
+	return &ForEachCommand{
+		objects: objects,
+		create:  create,
+	}
+}
+
+// Execute returns the errors of the failed commands, each with the id of its
+// object, joined in the order of the ids.
+func (c *ForEachCommand) Execute() error {
+	var errs []error
+	for _, id := range c.objects.IDs() {
+		target, err := c.objects.Get(id)
+		if err != nil {
+			continue
+		}
+
+		command := c.create(target)
+		if command == nil {
+			continue
+		}
+
+		err = command.Execute()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("object %q: %w", id, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
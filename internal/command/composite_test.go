@@ -0,0 +1,153 @@
+package command
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"modules/internal/core"
+	"modules/internal/ioc"
+	"modules/internal/mock"
+	"modules/internal/object"
+	"modules/internal/queue"
+)
+
+func TestComposite(t *testing.T) {
+	suite.Run(t, new(CompositeTestSuite))
+}
+
+type CompositeTestSuite struct {
+	suite.Suite
+
+	first  mock.CommandMock
+	second mock.CommandMock
+	third  mock.CommandMock
+}
+
+func (s *CompositeTestSuite) SetupTest() {
+	s.first = mock.CommandMock{}
+	s.second = mock.CommandMock{}
+	s.third = mock.CommandMock{}
+}
+
+func (s *CompositeTestSuite) TearDownTest() {
+	s.first.AssertExpectations(s.T())
+	s.second.AssertExpectations(s.T())
+	s.third.AssertExpectations(s.T())
+}
+
+func (s *CompositeTestSuite) TestParallel() {
+	var started sync.WaitGroup
+	started.Add(3)
+	wait := func(err error) func() error {
+		return func() error {
+			started.Done()
+			started.Wait()
+			return err
+		}
+	}
+	s.first.On("Execute").Return(wait(errSomeError))
+	s.second.On("Execute").Return(wait(nil))
+	s.third.On("Execute").Return(wait(errSomeOtherError))
+
+	err := NewParallelCommand(&s.first, &s.second, &s.third).Execute()
+	s.Require().ErrorIs(err, errSomeError)
+	s.Require().ErrorIs(err, errSomeOtherError)
+	s.Require().Equal("some error\nsome other error", err.Error())
+}
+
+func (s *CompositeTestSuite) TestParallelScope() {
+	s.Require().NoError(ioc.Resolve("Scopes.New", "composite_parallel").(core.Command).Execute())
+	err := ioc.Resolve("IoC.Register", "Game.ID", func(params ...interface{}) interface{} {
+		return "composite_parallel"
+	}).(core.Command).Execute()
+	s.Require().NoError(err)
+
+	var gameIDs sync.Map
+	resolve := func(key string) func() error {
+		return func() error {
+			gameIDs.Store(key, ioc.Resolve("Game.ID"))
+			return nil
+		}
+	}
+	s.first.On("Execute").Return(resolve("first"))
+	s.second.On("Execute").Return(resolve("second"))
+
+	s.Require().NoError(NewParallelCommand(&s.first, &s.second).Execute())
+	for _, key := range []string{"first", "second"} {
+		gameID, _ := gameIDs.Load(key)
+		s.Require().Equal("composite_parallel", gameID)
+	}
+}
+
+func (s *CompositeTestSuite) TestIf() {
+	s.first.On("Execute").Return(nil).Once()
+	s.second.On("Execute").Return(errSomeError)
+	s.Require().ErrorIs(NewIfCommand(&s.first, &s.second).WithElse(&s.third).Execute(), errSomeError)
+
+	s.first.On("Execute").Return(ErrNotEnoughFuel)
+	s.Require().NoError(NewIfCommand(&s.first, &s.second).Execute())
+	s.third.On("Execute").Return(nil)
+	s.Require().NoError(NewIfCommand(&s.first, &s.second).WithElse(&s.third).Execute())
+	s.second.AssertNumberOfCalls(s.T(), "Execute", 1)
+}
+
+func (s *CompositeTestSuite) TestFallback() {
+	s.first.On("Execute").Return(errSomeError)
+	s.second.On("Execute").Return(nil).Once()
+	s.Require().NoError(NewFallbackCommand(&s.first, &s.second, &s.third).Execute())
+
+	s.second.On("Execute").Return(errSomeOtherError)
+	err := NewFallbackCommand(&s.first, &s.second).Execute()
+	s.Require().ErrorIs(err, errSomeError)
+	s.Require().ErrorIs(err, errSomeOtherError)
+
+	s.Require().NoError(NewFallbackCommand().Execute())
+}
+
+func (s *CompositeTestSuite) TestForEach() {
+	objects := object.NewRegistry()
+	for _, id := range []string{"a", "b", "c"} {
+		s.Require().NoError(objects.Add(id, object.New(map[string]interface{}{object.Fuel: id})))
+	}
+
+	var visited []string
+	err := NewForEachCommand(objects, func(target core.Object) core.Command {
+		fuel, _ := target.GetProperty(object.Fuel)
+		if fuel == "b" {
+			return nil
+		}
+
+		result := &mock.CommandMock{}
+		result.On("Execute").Return(func() error {
+			visited = append(visited, fuel.(string))
+			if fuel == "c" {
+				s.Require().NoError(objects.Remove("a"))
+				return errSomeError
+			}
+			return nil
+		})
+		return result
+	}).Execute()
+	s.Require().ErrorIs(err, errSomeError)
+	s.Require().Equal(`object "c": some error`, err.Error())
+	s.Require().Equal([]string{"a", "c"}, visited)
+}
+
+func (s *CompositeTestSuite) TestListener() {
+	listener := queue.NewListener(2)
+	handled := make(chan error, 1)
+	listener.SetErrorHandler(func(command core.Command, err error) {
+		handled <- err
+	})
+	s.first.On("Execute").Return(errSomeError)
+	s.second.On("Execute").Return(nil)
+
+	listener.GetQueue().Put(NewParallelCommand(&s.first, &s.second))
+	listener.GetQueue().Put(listener.SoftStopCommand())
+	s.Require().NoError(listener.StartCommand().Execute())
+	<-listener.Done()
+
+	s.Require().ErrorIs(<-handled, errSomeError)
+}
@@ -1,12 +1,23 @@
 package command
 
-import "modules/internal/core"
+import (
+	"errors"
+
+	"modules/internal/core"
+)
 
 type LogErrorHandler struct {
 	queue   core.Queue
 	logFunc LogFunc
 }
 
+func NewLogErrorHandler(queue core.Queue, logFunc LogFunc) *LogErrorHandler {
+	return &LogErrorHandler{
+		queue:   queue,
+		logFunc: logFunc,
+	}
+}
+
 func (h *LogErrorHandler) Handle(command core.Command, err error) {
 	logCommand := LogCommand{
 		command: command,
@@ -22,11 +33,19 @@ type RepeatErrorHandler struct {
 	defaultHandler core.ErrorHandler
 }
 
+func NewRepeatErrorHandler(queue core.Queue, attempts int, defaultHandler core.ErrorHandler) *RepeatErrorHandler {
+	return &RepeatErrorHandler{
+		queue:          queue,
+		attempts:       attempts,
+		defaultHandler: defaultHandler,
+	}
+}
+
 func (h *RepeatErrorHandler) Handle(command core.Command, err error) {
 	repeatCommand, ok := command.(RepeatCommand)
 	if !ok {
 		h.queue.Put(RepeatCommand{
-			command: command,
+			command: retried(command, err),
 			attempt: 1,
 		})
 		return
@@ -37,6 +56,24 @@ func (h *RepeatErrorHandler) Handle(command core.Command, err error) {
 		return
 	}
 
+	repeatCommand.command = retried(repeatCommand.command, err)
 	repeatCommand.attempt += 1
 	h.queue.Put(repeatCommand)
 }
+
+// retried returns the command to repeat after the error. A MacroCommand goes
+// on from the failed step since the steps before it have taken effect. A
+// TransactionalMacroCommand has undone them, so it is repeated as a whole.
+func retried(command core.Command, err error) core.Command {
+	macro, ok := command.(*MacroCommand)
+	if !ok {
+		return command
+	}
+
+	var stepErr *StepError
+	if !errors.As(err, &stepErr) || stepErr.Macro != command {
+		return command
+	}
+
+	return macro.from(stepErr.Index)
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/stretchr/testify/suite"
 
+	"modules/internal/core"
 	"modules/internal/mock"
 )
 
@@ -119,3 +120,62 @@ func (s *ErrorHandlerSuite) TestRepeatTriple() {
 
 	s.queue.AssertExpectations(s.T())
 }
+
+func (s *ErrorHandlerSuite) TestRepeatMacroStep() {
+	h := RepeatErrorHandler{
+		queue:    &s.queue,
+		attempts: 2,
+	}
+
+	first, second, third := &mock.CommandMock{}, &mock.CommandMock{}, &mock.CommandMock{}
+	first.On("Execute").Return(nil)
+	second.On("Execute").Return(s.err).Once()
+	second.On("Execute").Return(nil)
+	macro := NewMacroCommand(first, second, third)
+	err := macro.Execute()
+
+	repeatCommand := RepeatCommand{
+		command: &MacroCommand{commands: []core.Command{second, third}},
+		attempt: 1,
+	}
+	s.queue.On("Put", repeatCommand).Return()
+	h.Handle(macro, err)
+
+	repeatCommand2 := repeatCommand
+	repeatCommand2.command = &MacroCommand{commands: []core.Command{third}}
+	repeatCommand2.attempt = 2
+	s.queue.On("Put", repeatCommand2).Return()
+	third.On("Execute").Return(s.err)
+	h.Handle(repeatCommand, repeatCommand.Execute())
+
+	s.queue.AssertExpectations(s.T())
+	first.AssertNumberOfCalls(s.T(), "Execute", 1)
+}
+
+func (s *ErrorHandlerSuite) TestRepeatTransactionalMacro() {
+	h := RepeatErrorHandler{
+		queue: &s.queue,
+	}
+
+	s.command.On("Execute").Return(s.err)
+	macro := NewTransactionalMacroCommand(&mock.UndoableMock{}, s.command)
+	s.queue.On("Put", RepeatCommand{command: macro, attempt: 1}).Return()
+	h.Handle(macro, &StepError{Macro: macro, Command: s.command, Index: 1, Err: s.err})
+	s.queue.AssertExpectations(s.T())
+}
+
+func (s *ErrorHandlerSuite) TestLogMacroStep() {
+	s.command.On("Execute").Return(ErrNotEnoughFuel)
+	macro := NewMacroCommand(s.command)
+
+	var message string
+	logCommand := LogCommand{
+		command: macro,
+		err:     macro.Execute(),
+		logFunc: func(m string) {
+			message = m
+		},
+	}
+	s.Require().NoError(logCommand.Execute())
+	s.Require().Equal("MacroCommand got error: 'step 0 (CommandMock): not enough fuel'", message)
+}
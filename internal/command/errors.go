@@ -1,9 +1,77 @@
 package command
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+
+	"modules/internal/core"
+)
 
 var (
 	ErrNotEnoughFuel = fmt.Errorf("not enough fuel")
 
 	ErrUnsupportedDimension = fmt.Errorf("unsupported dimension")
+
+	ErrPermissionDenied = fmt.Errorf("permission denied")
+
+	ErrNotRepeating = fmt.Errorf("no repeating command to end")
+
+	ErrNoAmmo = fmt.Errorf("no ammo")
+
+	ErrCooldown = fmt.Errorf("weapon is cooling down")
+
+	ErrNotExecuted = fmt.Errorf("command has not been executed")
+
+	ErrUnknownCommand = fmt.Errorf("unknown command")
+
+	ErrUnknownTarget = fmt.Errorf("command target is not a game object")
+
+	ErrInvalidEncoding = fmt.Errorf("invalid command encoding")
 )
+
+// PermissionError is returned by CheckPermissionCommand. It matches
+// ErrPermissionDenied with errors.Is.
+type PermissionError struct {
+	PlayerID   string
+	Permission string
+}
+
+func (e *PermissionError) Error() string {
+	return fmt.Sprintf("%s: player %q may not %s the object", ErrPermissionDenied, e.PlayerID, e.Permission)
+}
+
+func (e *PermissionError) Unwrap() error {
+	return ErrPermissionDenied
+}
+
+// CooldownError is returned by ShootCommand before the cooldown since the
+// last shot has passed. It matches ErrCooldown with errors.Is.
+type CooldownError struct {
+	Remaining time.Duration
+}
+
+func (e *CooldownError) Error() string {
+	return fmt.Sprintf("%s: %s left", ErrCooldown, e.Remaining)
+}
+
+func (e *CooldownError) Unwrap() error {
+	return ErrCooldown
+}
+
+// StepError is returned by MacroCommand and TransactionalMacroCommand when
+// one of their commands fails. It matches the error of the command with
+// errors.Is and errors.As.
+type StepError struct {
+	Macro   core.Command
+	Command core.Command
+	Index   int
+	Err     error
+}
+
+func (e *StepError) Error() string {
+	return fmt.Sprintf("step %d (%s): %s", e.Index, TypeName(e.Command), e.Err)
+}
+
+func (e *StepError) Unwrap() error {
+	return e.Err
+}
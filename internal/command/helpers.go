@@ -1,11 +1,25 @@
 package command
 
-import "reflect"
+import (
+	"math"
+	"reflect"
 
-func getType(v interface{}) string {
+	"modules/internal/vector"
+)
+
+// TypeName returns the name of the type of v, the element type for a
+// pointer, e.g. to tell which command failed.
+func TypeName(v interface{}) string {
 	if t := reflect.TypeOf(v); t.Kind() == reflect.Ptr {
 		return t.Elem().Name()
 	} else {
 		return t.Name()
 	}
 }
+
+// directionVector returns a 2D vector of the length pointing in the direction,
+// one of n directions evenly dividing the full circle.
+func directionVector(length float64, direction, n int) vector.Vector {
+	alpha := float64(direction) / float64(n) * 2 * math.Pi
+	return vector.New([]int{int(length * math.Cos(alpha)), int(length * math.Sin(alpha))})
+}
@@ -0,0 +1,244 @@
+package command
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"modules/internal/core"
+)
+
+type CancellationToken struct {
+	cancelled atomic.Bool
+}
+
+func NewCancellationToken() *CancellationToken {
+	return &CancellationToken{}
+}
+
+func (t *CancellationToken) Cancel() {
+	t.cancelled.Store(true)
+}
+
+func (t *CancellationToken) IsCancelled() bool {
+	return t.cancelled.Load()
+}
+
+// RepeatingCommand executes the command and puts itself back into the queue
+// until the token is cancelled, the command fails, the stop predicate holds
+// or the command has been executed the maximum number of times. It ends on
+// its own when the listener stops since the queue then drops it. A token kept
+// in Tokens is released once the command ends without being cancelled, see
+// WithTokens.
+//
+// Unlike RepeatCommand, which retries a failed command, it repeats a command
+// that succeeds.
+type RepeatingCommand struct {
+	queue         core.Queue
+	command       core.Command
+	token         *CancellationToken
+	maxIterations int
+	stop          func() bool
+	tokens        *Tokens
+	key           interface{}
+
+	iterations int
+}
+
+func NewRepeatingCommand(queue core.Queue, command core.Command, token *CancellationToken) *RepeatingCommand {
+	return &RepeatingCommand{
+		queue:   queue,
+		command: command,
+		token:   token,
+	}
+}
+
+// WithMaxIterations limits the number of executions, zero means no limit.
+func (c *RepeatingCommand) WithMaxIterations(maxIterations int) *RepeatingCommand {
+	c.maxIterations = maxIterations
+	return c
+}
+
+// WithStopPredicate makes the command stop once stop returns true. It is
+// checked before every execution.
+func (c *RepeatingCommand) WithStopPredicate(stop func() bool) *RepeatingCommand {
+	c.stop = stop
+	return c
+}
+
+// WithTokens tells the command that its token is kept in tokens under the
+// key, so that it forgets the token once it ends on its own. An order
+// cancelling the token then fails like for a command that never ran.
+func (c *RepeatingCommand) WithTokens(tokens *Tokens, key interface{}) *RepeatingCommand {
+	c.tokens = tokens
+	c.key = key
+	return c
+}
+
+func (c *RepeatingCommand) Iterations() int {
+	return c.iterations
+}
+
+// Unwrap returns the repeated command.
+func (c *RepeatingCommand) Unwrap() core.Command {
+	return c.command
+}
+
+func (c *RepeatingCommand) Execute() error {
+	if c.token.IsCancelled() {
+		return nil
+	}
+
+	if c.stop != nil && c.stop() {
+		c.release()
+		return nil
+	}
+
+	err := c.command.Execute()
+	if err != nil {
+		c.release()
+		return err
+	}
+
+	c.iterations++
+	if c.maxIterations > 0 && c.iterations >= c.maxIterations {
+		c.release()
+		return nil
+	}
+
+	PutBack(c.queue, c)
+	return nil
+}
+
+func (c *RepeatingCommand) release() {
+	if c.tokens != nil {
+		c.tokens.Release(c.key, c.token)
+	}
+}
+
+// PutBack puts the command into the queue from a command executed by the
+// listener reading that queue. Waiting there for room in a full queue would
+// block the listener forever, so the command is then put from a separate
+// goroutine instead.
+func PutBack(queue core.Queue, command core.Command) {
+	if q, ok := queue.(interface{ TryPut(core.Command) bool }); ok && q.TryPut(command) {
+		return
+	}
+
+	go queue.Put(command)
+}
+
+// Tokens keeps the cancellation tokens of long-running commands by key, e.g.
+// by the game object the command controls.
+type Tokens struct {
+	mutex  sync.Mutex
+	tokens map[interface{}]*CancellationToken
+}
+
+func NewTokens() *Tokens {
+	return &Tokens{
+		tokens: map[interface{}]*CancellationToken{},
+	}
+}
+
+// New cancels the token kept under the key and replaces it with a new one.
+func (t *Tokens) New(key interface{}) *CancellationToken {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if token, ok := t.tokens[key]; ok {
+		token.Cancel()
+	}
+
+	result := NewCancellationToken()
+	t.tokens[key] = result
+	return result
+}
+
+// Cancel cancels and forgets the token kept under the key. It returns false
+// if there is no token or it has been cancelled already.
+func (t *Tokens) Cancel(key interface{}) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	token, ok := t.tokens[key]
+	if !ok {
+		return false
+	}
+
+	delete(t.tokens, key)
+	if token.IsCancelled() {
+		return false
+	}
+
+	token.Cancel()
+	return true
+}
+
+// Release forgets the token kept under the key unless it has been replaced
+// by another one.
+func (t *Tokens) Release(key interface{}, token *CancellationToken) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.tokens[key] == token {
+		delete(t.tokens, key)
+	}
+}
+
+// current returns the token kept under the key, if any.
+func (t *Tokens) current(key interface{}) *CancellationToken {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return t.tokens[key]
+}
+
+type moveKey struct {
+	object interface{}
+}
+
+// BeginMoveCommand puts a RepeatingCommand executing the move command, e.g. a
+// MoveCommand, into the queue. The movement goes on until EndMoveCommand is
+// executed for the same object.
+type BeginMoveCommand struct {
+	queue  core.Queue
+	tokens *Tokens
+	object interface{}
+	move   core.Command
+}
+
+func NewBeginMoveCommand(queue core.Queue, tokens *Tokens, object interface{}, move core.Command) *BeginMoveCommand {
+	return &BeginMoveCommand{
+		queue:  queue,
+		tokens: tokens,
+		object: object,
+		move:   move,
+	}
+}
+
+func (c *BeginMoveCommand) Execute() error {
+	key := moveKey{object: c.object}
+	token := c.tokens.New(key)
+	PutBack(c.queue, NewRepeatingCommand(c.queue, c.move, token).WithTokens(c.tokens, key))
+	return nil
+}
+
+type EndMoveCommand struct {
+	tokens *Tokens
+	object interface{}
+}
+
+func NewEndMoveCommand(tokens *Tokens, object interface{}) *EndMoveCommand {
+	return &EndMoveCommand{
+		tokens: tokens,
+		object: object,
+	}
+}
+
+func (c *EndMoveCommand) Execute() error {
+	if !c.tokens.Cancel(moveKey{object: c.object}) {
+		return ErrNotRepeating
+	}
+
+	return nil
+}
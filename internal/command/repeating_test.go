@@ -0,0 +1,202 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"modules/internal/core"
+	"modules/internal/mock"
+	"modules/internal/queue"
+	"modules/internal/vector"
+)
+
+type sliceQueue struct {
+	commands []core.Command
+	full     bool
+	put      chan core.Command
+}
+
+func (q *sliceQueue) Put(command core.Command) {
+	q.put <- command
+}
+
+func (q *sliceQueue) TryPut(command core.Command) bool {
+	if q.full {
+		return false
+	}
+	q.commands = append(q.commands, command)
+	return true
+}
+
+// run executes the queued commands like a listener would.
+func (q *sliceQueue) run() (errs []error) {
+	for len(q.commands) != 0 {
+		command := q.commands[0]
+		q.commands = q.commands[1:]
+		if err := command.Execute(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+func TestRepeating(t *testing.T) {
+	suite.Run(t, new(RepeatingTestSuite))
+}
+
+type RepeatingTestSuite struct {
+	suite.Suite
+
+	queue   *sliceQueue
+	command mock.CommandMock
+	token   *CancellationToken
+}
+
+func (s *RepeatingTestSuite) SetupTest() {
+	s.queue = &sliceQueue{put: make(chan core.Command, 1)}
+	s.command = mock.CommandMock{}
+	s.token = NewCancellationToken()
+}
+
+func (s *RepeatingTestSuite) TestMaxIterations() {
+	s.command.On("Execute").Return(nil)
+	repeating := NewRepeatingCommand(s.queue, &s.command, s.token).WithMaxIterations(5)
+
+	s.Require().NoError(repeating.Execute())
+	s.Require().Empty(s.queue.run())
+	s.Require().Equal(5, repeating.Iterations())
+	s.command.AssertNumberOfCalls(s.T(), "Execute", 5)
+}
+
+func (s *RepeatingTestSuite) TestCancel() {
+	repeating := NewRepeatingCommand(s.queue, &s.command, s.token)
+	s.command.On("Execute").Return(func() error {
+		if repeating.Iterations() == 2 {
+			s.token.Cancel()
+		}
+		return nil
+	})
+
+	s.Require().NoError(repeating.Execute())
+	s.Require().Empty(s.queue.run())
+	s.Require().Equal(3, repeating.Iterations())
+}
+
+func (s *RepeatingTestSuite) TestStopPredicate() {
+	s.command.On("Execute").Return(nil)
+	var repeating *RepeatingCommand
+	repeating = NewRepeatingCommand(s.queue, &s.command, s.token).WithStopPredicate(func() bool {
+		return repeating.Iterations() == 4
+	})
+
+	s.Require().NoError(repeating.Execute())
+	s.Require().Empty(s.queue.run())
+	s.command.AssertNumberOfCalls(s.T(), "Execute", 4)
+}
+
+func (s *RepeatingTestSuite) TestError() {
+	repeating := NewRepeatingCommand(s.queue, &s.command, s.token)
+	s.command.On("Execute").Return(func() error {
+		if repeating.Iterations() == 1 {
+			return errSomeError
+		}
+		return nil
+	})
+
+	s.Require().NoError(repeating.Execute())
+	s.Require().Equal([]error{errSomeError}, s.queue.run())
+	s.command.AssertNumberOfCalls(s.T(), "Execute", 2)
+}
+
+func (s *RepeatingTestSuite) TestFullQueue() {
+	s.command.On("Execute").Return(nil)
+	repeating := NewRepeatingCommand(s.queue, &s.command, s.token)
+
+	s.queue.full = true
+	s.Require().NoError(repeating.Execute())
+	s.Require().Empty(s.queue.commands)
+	s.Require().Same(repeating, <-s.queue.put)
+}
+
+func (s *RepeatingTestSuite) TestListenerStop() {
+	for _, hard := range []bool{false, true} {
+		listener := queue.NewListener(1)
+		executed := make(chan struct{})
+		command := mock.CommandMock{}
+		command.On("Execute").Return(func() error {
+			select {
+			case executed <- struct{}{}:
+			default:
+			}
+			return nil
+		})
+
+		listener.GetQueue().Put(NewRepeatingCommand(listener.GetQueue(), &command, s.token))
+		s.Require().NoError(listener.StartCommand().Execute())
+		<-executed
+		<-executed
+
+		if hard {
+			s.Require().NoError(listener.HardStopCommand().Execute())
+		} else {
+			listener.GetQueue().Put(listener.SoftStopCommand())
+		}
+		<-listener.Done()
+	}
+}
+
+func (s *RepeatingTestSuite) TestBeginEndMove() {
+	movable := mock.MovableMock{}
+	movable.On("GetPosition").Return(vector.New([]int{12, 5}), nil).
+		On("GetVelocity").Return(vector.New([]int{-7, 3}), nil).
+		On("SetPosition", vector.New([]int{5, 8})).Return(nil)
+
+	tokens := NewTokens()
+	ship := struct{ name string }{"548"}
+	s.Require().NoError(NewBeginMoveCommand(s.queue, tokens, ship, NewMoveCommand(&movable)).Execute())
+	s.Require().Len(s.queue.commands, 1)
+
+	for i := 0; i < 3; i++ {
+		command := s.queue.commands[0]
+		s.queue.commands = s.queue.commands[1:]
+		s.Require().NoError(command.Execute())
+	}
+	movable.AssertNumberOfCalls(s.T(), "SetPosition", 3)
+
+	s.Require().NoError(NewEndMoveCommand(tokens, ship).Execute())
+	s.Require().ErrorIs(NewEndMoveCommand(tokens, ship).Execute(), ErrNotRepeating)
+	s.Require().Empty(s.queue.run())
+	movable.AssertNumberOfCalls(s.T(), "SetPosition", 3)
+}
+
+func (s *RepeatingTestSuite) TestFailedMove() {
+	s.command.On("Execute").Return(errSomeError)
+	tokens := NewTokens()
+	ship := struct{ name string }{"548"}
+	s.Require().NoError(NewBeginMoveCommand(s.queue, tokens, ship, &s.command).Execute())
+	s.Require().Equal([]error{errSomeError}, s.queue.run())
+
+	s.Require().ErrorIs(NewEndMoveCommand(tokens, ship).Execute(), ErrNotRepeating)
+	s.Require().Empty(tokens.tokens)
+}
+
+func (s *RepeatingTestSuite) TestTokens() {
+	tokens := NewTokens()
+	first := tokens.New("a")
+	second := tokens.New("a")
+	s.Require().True(first.IsCancelled())
+	s.Require().False(second.IsCancelled())
+
+	s.Require().True(tokens.Cancel("a"))
+	s.Require().True(second.IsCancelled())
+	s.Require().False(tokens.Cancel("a"))
+
+	tokens.New("b").Cancel()
+	s.Require().False(tokens.Cancel("b"))
+
+	replaced := tokens.New("c")
+	tokens.New("c")
+	tokens.Release("c", replaced)
+	s.Require().True(tokens.Cancel("c"))
+}
@@ -0,0 +1,125 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"modules/internal/clock"
+	"modules/internal/core"
+	"modules/internal/vector"
+)
+
+// ProjectileFactory creates a projectile game object with the position and
+// velocity and adds it to the game. It returns the command that starts the
+// projectile moving, e.g. on the game scheduler.
+type ProjectileFactory func(position, velocity vector.Vector) (core.Command, error)
+
+// ShootCommand spends a round of ammo to create a projectile at the position
+// of the shooter flying in its direction and starts the projectile moving.
+type ShootCommand struct {
+	shooter core.Shooter
+	clock   clock.Clock
+	spawn   ProjectileFactory
+
+	// now is the time of the last execution, which the codec encodes so
+	// that a replay shoots at the same time.
+	now time.Time
+}
+
+func NewShootCommand(shooter core.Shooter, clock clock.Clock, spawn ProjectileFactory) *ShootCommand {
+	return &ShootCommand{
+		shooter: shooter,
+		clock:   clock,
+		spawn:   spawn,
+	}
+}
+
+func (c *ShootCommand) Execute() error {
+	ammo, err := c.shooter.GetAmmo()
+	if err != nil {
+		return err
+	}
+
+	if ammo <= 0 {
+		return ErrNoAmmo
+	}
+
+	lastShot, err := c.shooter.GetLastShot()
+	if err != nil {
+		return err
+	}
+
+	now := c.clock.Now()
+	c.now = now
+	err = c.checkCooldown(lastShot, now)
+	if err != nil {
+		return err
+	}
+
+	velocity, err := c.projectileVelocity()
+	if err != nil {
+		return err
+	}
+
+	position, err := c.shooter.GetPosition()
+	if err != nil {
+		return err
+	}
+
+	// the shot is paid for before the projectile exists, so a failure never
+	// leaves a free projectile behind
+	err = c.shooter.SetAmmo(ammo - 1)
+	if err != nil {
+		return err
+	}
+
+	err = c.shooter.SetLastShot(now)
+	if err != nil {
+		return errors.Join(err, c.shooter.SetAmmo(ammo))
+	}
+
+	start, err := c.spawn(position, velocity)
+	if err != nil {
+		return errors.Join(fmt.Errorf("spawn projectile: %w", err), c.shooter.SetAmmo(ammo), c.shooter.SetLastShot(lastShot))
+	}
+
+	return start.Execute()
+}
+
+func (c *ShootCommand) checkCooldown(lastShot, now time.Time) error {
+	if lastShot.IsZero() {
+		return nil
+	}
+
+	cooldown, err := c.shooter.GetCooldown()
+	if err != nil {
+		return err
+	}
+
+	ready := lastShot.Add(cooldown)
+	if now.Before(ready) {
+		return &CooldownError{Remaining: ready.Sub(now)}
+	}
+
+	return nil
+}
+
+func (c *ShootCommand) projectileVelocity() (vector.Vector, error) {
+	speed, err := c.shooter.GetProjectileSpeed()
+	if err != nil {
+		return nil, err
+	}
+
+	direction, err := c.shooter.GetDirection()
+	if err != nil {
+		return nil, err
+	}
+
+	n, err := c.shooter.GetDirectionsNumber()
+	if err != nil {
+		return nil, err
+	}
+
+	return directionVector(float64(speed), direction, n), nil
+}
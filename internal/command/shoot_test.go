@@ -0,0 +1,143 @@
+package command
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"modules/internal/clock"
+	"modules/internal/core"
+	"modules/internal/mock"
+	"modules/internal/object"
+	"modules/internal/vector"
+)
+
+func TestShoot(t *testing.T) {
+	suite.Run(t, new(ShootTestSuite))
+}
+
+type ShootTestSuite struct {
+	suite.Suite
+
+	clock       *clock.Fake
+	ship        *object.Object
+	shooter     core.Shooter
+	projectiles []*object.Object
+	started     []*object.Object
+	spawnErr    error
+}
+
+type failingAmmo struct {
+	core.Shooter
+}
+
+func (failingAmmo) SetAmmo(int) error {
+	return errSomeError
+}
+
+func (s *ShootTestSuite) SetupTest() {
+	s.clock = clock.NewFake(time.Unix(1000, 0))
+	s.spawnErr = nil
+	s.started = nil
+	s.ship = object.New(map[string]interface{}{
+		object.Position:         vector.New([]int{12, 5}),
+		object.Direction:        2,
+		object.DirectionsNumber: 8,
+		object.Ammo:             2,
+		object.Cooldown:         500,
+		object.ProjectileSpeed:  10,
+	})
+	s.shooter = object.NewAdapter(s.ship)
+	s.projectiles = nil
+}
+
+func (s *ShootTestSuite) spawn(position, velocity vector.Vector) (core.Command, error) {
+	if s.spawnErr != nil {
+		return nil, s.spawnErr
+	}
+
+	projectile := object.New(map[string]interface{}{
+		object.Position: position,
+		object.Velocity: velocity,
+	})
+	s.projectiles = append(s.projectiles, projectile)
+
+	start := &mock.CommandMock{}
+	start.On("Execute").Return(func() error {
+		s.started = append(s.started, projectile)
+		return nil
+	})
+	return start, nil
+}
+
+func (s *ShootTestSuite) shoot() error {
+	return NewShootCommand(s.shooter, s.clock, s.spawn).Execute()
+}
+
+func (s *ShootTestSuite) TestShoot() {
+	s.Require().NoError(s.shoot())
+	s.Require().Len(s.projectiles, 1)
+	s.Require().Equal(map[string]interface{}{
+		object.Position: vector.New([]int{12, 5}),
+		object.Velocity: vector.New([]int{0, 10}),
+	}, s.projectiles[0].Properties())
+
+	s.Require().Equal(s.projectiles, s.started)
+
+	properties := s.ship.Properties()
+	s.Require().Equal(1, properties[object.Ammo])
+	s.Require().Equal(1000000, properties[object.LastShot])
+}
+
+func (s *ShootTestSuite) TestSetAmmoError() {
+	s.shooter = failingAmmo{Shooter: object.NewAdapter(s.ship)}
+	s.Require().ErrorIs(s.shoot(), errSomeError)
+	s.Require().Empty(s.projectiles)
+	s.Require().NotContains(s.ship.Properties(), object.LastShot)
+}
+
+func (s *ShootTestSuite) TestSpawnError() {
+	s.Require().NoError(s.ship.SetProperty(object.Cooldown, 0))
+	s.Require().NoError(s.shoot())
+
+	s.spawnErr = errSomeError
+	s.clock.Advance(time.Second)
+	s.Require().ErrorIs(s.shoot(), errSomeError)
+
+	properties := s.ship.Properties()
+	s.Require().Equal(1, properties[object.Ammo])
+	s.Require().Equal(1000000, properties[object.LastShot])
+}
+
+func (s *ShootTestSuite) TestCooldown() {
+	s.Require().NoError(s.shoot())
+
+	s.clock.Advance(200 * time.Millisecond)
+	err := s.shoot()
+	s.Require().ErrorIs(err, ErrCooldown)
+	s.Require().Equal(&CooldownError{Remaining: 300 * time.Millisecond}, err)
+
+	s.clock.Advance(300 * time.Millisecond)
+	s.Require().NoError(s.shoot())
+	s.Require().Len(s.projectiles, 2)
+}
+
+func (s *ShootTestSuite) TestNoAmmo() {
+	s.Require().NoError(s.ship.SetProperty(object.Ammo, 0))
+	s.Require().ErrorIs(s.shoot(), ErrNoAmmo)
+	s.Require().Empty(s.projectiles)
+
+	s.Require().NoError(s.ship.SetProperty(object.Ammo, 1))
+	s.Require().NoError(s.ship.SetProperty(object.Cooldown, 0))
+	s.Require().NoError(s.shoot())
+	s.Require().ErrorIs(s.shoot(), ErrNoAmmo)
+	s.Require().Len(s.projectiles, 1)
+}
+
+func (s *ShootTestSuite) TestNotShooter() {
+	s.Require().NoError(s.ship.SetProperty(object.ProjectileSpeed, "fast"))
+	s.Require().ErrorIs(s.shoot(), object.ErrInvalidProperty)
+	s.Require().Empty(s.projectiles)
+	s.Require().Equal(2, s.ship.Properties()[object.Ammo])
+}
@@ -0,0 +1,180 @@
+package command
+
+import (
+	"math"
+
+	"modules/internal/core"
+	"modules/internal/vector"
+)
+
+// AccelerateCommand adds the thrust of the object to its velocity along its
+// direction. The speed is then scaled down to the maximum speed, if any. A
+// negative thrust slows the object down.
+type AccelerateCommand struct {
+	object core.Thrustable
+
+	previous vector.Vector
+	executed bool
+}
+
+func NewAccelerateCommand(object core.Thrustable) *AccelerateCommand {
+	return &AccelerateCommand{
+		object: object,
+	}
+}
+
+func (c *AccelerateCommand) Execute() error {
+	c.executed = false
+	velocity, err := c.object.GetVelocity()
+	if err != nil {
+		return err
+	}
+
+	if len(velocity) != 2 {
+		return ErrUnsupportedDimension
+	}
+
+	thrust, err := c.object.GetThrust()
+	if err != nil {
+		return err
+	}
+
+	direction, err := c.object.GetDirection()
+	if err != nil {
+		return err
+	}
+
+	n, err := c.object.GetDirectionsNumber()
+	if err != nil {
+		return err
+	}
+
+	maxSpeed, err := c.object.GetMaxSpeed()
+	if err != nil {
+		return err
+	}
+
+	accelerated := vector.Add(velocity, directionVector(float64(thrust), direction, n))
+	err = c.object.SetVelocity(LimitSpeed(accelerated, maxSpeed))
+	if err != nil {
+		return err
+	}
+
+	c.previous = velocity
+	c.executed = true
+	return nil
+}
+
+func (c *AccelerateCommand) Undo() error {
+	if !c.executed {
+		return ErrNotExecuted
+	}
+
+	err := c.object.SetVelocity(c.previous)
+	if err != nil {
+		return err
+	}
+
+	c.executed = false
+	return nil
+}
+
+// LimitSpeed scales the velocity down to the maximum speed. A maximum speed
+// of zero sets no limit.
+func LimitSpeed(velocity vector.Vector, maxSpeed int) vector.Vector {
+	if maxSpeed <= 0 {
+		return velocity
+	}
+
+	var squared float64
+	for _, v := range velocity {
+		squared += float64(v) * float64(v)
+	}
+
+	speed := math.Sqrt(squared)
+	if speed <= float64(maxSpeed) {
+		return velocity
+	}
+
+	scale := float64(maxSpeed) / speed
+	result := make([]int, len(velocity))
+	for i, v := range velocity {
+		result[i] = int(float64(v) * scale)
+	}
+
+	return vector.New(result)
+}
+
+// thrustFuel makes the fuel commands burn the thrust consumption for every
+// unit of thrust.
+type thrustFuel struct {
+	core.ThrustableWithFuel
+}
+
+func (f thrustFuel) GetConsumption() (int, error) {
+	thrust, err := f.GetThrust()
+	if err != nil {
+		return 0, err
+	}
+
+	consumption, err := f.GetThrustConsumption()
+	if err != nil {
+		return 0, err
+	}
+
+	if thrust < 0 {
+		thrust = -thrust
+	}
+
+	return thrust * consumption, nil
+}
+
+// ThrustCommand accelerates the object if it has the fuel for the thrust.
+type ThrustCommand struct {
+	*TransactionalMacroCommand
+	object core.ThrustableWithFuel
+}
+
+func NewThrustCommand(object core.ThrustableWithFuel) *ThrustCommand {
+	fuel := thrustFuel{object}
+	return &ThrustCommand{
+		TransactionalMacroCommand: NewTransactionalMacroCommand(NewCheckFuelCommand(fuel), NewAccelerateCommand(object), NewBurnFuelCommand(fuel)),
+		object:                    object,
+	}
+}
+
+// DragCommand takes the drag percentage of the object off its velocity. It
+// follows every move step, so a continuous movement slows down on every
+// tick.
+type DragCommand struct {
+	object core.Dragging
+}
+
+func NewDragCommand(object core.Dragging) *DragCommand {
+	return &DragCommand{
+		object: object,
+	}
+}
+
+func (c *DragCommand) Execute() error {
+	drag, err := c.object.GetDrag()
+	if err != nil {
+		return err
+	}
+
+	if drag == 0 {
+		return nil
+	}
+
+	velocity, err := c.object.GetVelocity()
+	if err != nil {
+		return err
+	}
+
+	slowed := make([]int, len(velocity))
+	for i, v := range velocity {
+		slowed[i] = v * (100 - drag) / 100
+	}
+
+	return c.object.SetVelocity(vector.New(slowed))
+}
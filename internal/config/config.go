@@ -0,0 +1,294 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"modules/internal/core"
+	"modules/internal/ioc"
+)
+
+type Config struct {
+	Aliases       map[string]string `yaml:"aliases" json:"aliases"`
+	Registrations []Registration    `yaml:"registrations" json:"registrations"`
+	Scopes        []ScopeConfig     `yaml:"scopes" json:"scopes"`
+}
+
+type ScopeConfig struct {
+	Name          string            `yaml:"name" json:"name"`
+	Parent        string            `yaml:"parent" json:"parent"`
+	Aliases       map[string]string `yaml:"aliases" json:"aliases"`
+	Registrations []Registration    `yaml:"registrations" json:"registrations"`
+}
+
+type Registration struct {
+	Key     string                 `yaml:"key" json:"key"`
+	Factory string                 `yaml:"factory" json:"factory"`
+	Params  map[string]interface{} `yaml:"params" json:"params"`
+}
+
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return ParseJSON(data)
+	case ".yaml", ".yml":
+		return ParseYAML(data)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownFormat, path)
+	}
+}
+
+func ParseJSON(data []byte) (*Config, error) {
+	result := &Config{}
+	err := json.Unmarshal(data, result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func ParseYAML(data []byte) (*Config, error) {
+	result := &Config{}
+	err := yaml.Unmarshal(data, result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+type registration struct {
+	key    string
+	create func(params ...interface{}) interface{}
+}
+
+type scopePlan struct {
+	name          string
+	parent        string
+	registrations []registration
+	aliases       map[string]string
+}
+
+// Validate checks that every registration refers to a known factory with
+// acceptable params and that no aliases resolve each other in a cycle
+// without touching ioc.
+func (c *Config) Validate() error {
+	_, err := c.plan()
+	return err
+}
+
+// Apply registers everything described by the config via "IoC.Register".
+// Nothing is registered if the config is invalid or a parent scope neither
+// exists nor is created by the config. The current scope of the calling
+// goroutine is left unchanged.
+func (c *Config) Apply() error {
+	plans, err := c.plan()
+	if err != nil {
+		return err
+	}
+
+	err = checkParents(plans)
+	if err != nil {
+		return err
+	}
+
+	for _, plan := range plans {
+		err = runInNewGoroutine(plan.apply)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *Config) plan() ([]scopePlan, error) {
+	root, err := newScopePlan("", "", c.Aliases, c.Registrations)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []scopePlan{root}
+	for _, scope := range c.Scopes {
+		if scope.Name == "" {
+			return nil, ErrEmptyScopeName
+		}
+
+		plan, err := newScopePlan(scope.Name, scope.Parent, scope.Aliases, scope.Registrations)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, plan)
+	}
+
+	err = checkAliases(result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// checkAliases follows the aliases visible in every scope, including those of
+// the parent scopes created by the config, and fails on a cycle, which would
+// make resolving the alias recurse forever.
+func checkAliases(plans []scopePlan) error {
+	byName := map[string]scopePlan{}
+	for _, plan := range plans {
+		byName[plan.name] = plan
+	}
+
+	for _, plan := range plans {
+		aliases := visibleAliases(plan, byName, map[string]bool{})
+		for alias := range aliases {
+			seen := map[string]bool{alias: true}
+			for key := aliases[alias]; ; key = aliases[key] {
+				if _, ok := aliases[key]; !ok {
+					break
+				}
+				if seen[key] {
+					return fmt.Errorf("%w: scope %q, alias %q", ErrAliasCycle, plan.name, alias)
+				}
+				seen[key] = true
+			}
+		}
+	}
+
+	return nil
+}
+
+// visibleAliases returns the aliases of the scope and its ancestors which
+// are not overridden by a registration. Scopes without a parent are children
+// of the root scope.
+func visibleAliases(plan scopePlan, byName map[string]scopePlan, visited map[string]bool) map[string]string {
+	result := map[string]string{}
+	if plan.name != "" && !visited[plan.name] {
+		visited[plan.name] = true
+		if parent, ok := byName[plan.parent]; ok {
+			result = visibleAliases(parent, byName, visited)
+		}
+	}
+
+	for _, r := range plan.registrations {
+		delete(result, r.key)
+	}
+	for alias, target := range plan.aliases {
+		result[alias] = target
+	}
+
+	return result
+}
+
+// checkParents makes sure every parent scope exists before anything is
+// registered. A parent created by an earlier scope of the config is fine.
+func checkParents(plans []scopePlan) error {
+	created := map[string]bool{}
+	for _, plan := range plans {
+		if plan.parent != "" && !created[plan.parent] {
+			err := runInNewGoroutine(func() error {
+				return ioc.Resolve("Scopes.Current", plan.parent).(core.Command).Execute()
+			})
+			if err != nil {
+				return fmt.Errorf("parent of scope %q: %w", plan.name, err)
+			}
+		}
+		created[plan.name] = true
+	}
+
+	return nil
+}
+
+func newScopePlan(name, parent string, aliases map[string]string, registrations []Registration) (scopePlan, error) {
+	result := scopePlan{
+		name:    name,
+		parent:  parent,
+		aliases: aliases,
+	}
+
+	for _, r := range registrations {
+		if r.Key == "" {
+			return result, fmt.Errorf("%w: scope %q, factory %q", ErrEmptyKey, name, r.Factory)
+		}
+
+		factory, ok := getFactory(r.Factory)
+		if !ok {
+			return result, fmt.Errorf("%w: %q (scope %q, key %q)", ErrUnknownFactory, r.Factory, name, r.Key)
+		}
+
+		create, err := factory(r.Params)
+		if err != nil {
+			return result, fmt.Errorf("factory %q (scope %q, key %q): %w", r.Factory, name, r.Key, err)
+		}
+
+		result.registrations = append(result.registrations, registration{
+			key:    r.Key,
+			create: create,
+		})
+	}
+
+	for alias, target := range aliases {
+		if alias == "" || target == "" {
+			return result, fmt.Errorf("%w: scope %q, alias %q -> %q", ErrEmptyKey, name, alias, target)
+		}
+	}
+
+	return result, nil
+}
+
+func newAlias(target string) func(params ...interface{}) interface{} {
+	return func(params ...interface{}) interface{} {
+		return ioc.Resolve(target, params...)
+	}
+}
+
+func (p scopePlan) apply() error {
+	if p.parent != "" {
+		err := ioc.Resolve("Scopes.Current", p.parent).(core.Command).Execute()
+		if err != nil {
+			return fmt.Errorf("parent of scope %q: %w", p.name, err)
+		}
+	}
+
+	if p.name != "" {
+		err := ioc.Resolve("Scopes.New", p.name).(core.Command).Execute()
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, r := range p.registrations {
+		err := ioc.Resolve("IoC.Register", r.key, r.create).(core.Command).Execute()
+		if err != nil {
+			return err
+		}
+	}
+
+	for alias, target := range p.aliases {
+		err := ioc.Resolve("IoC.Register", alias, newAlias(target)).(core.Command).Execute()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func runInNewGoroutine(f func() error) error {
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- f()
+	}()
+
+	return <-errChan
+}
@@ -0,0 +1,172 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"modules/internal/command"
+	"modules/internal/core"
+	"modules/internal/ioc"
+	"modules/internal/mock"
+)
+
+func TestConfig(t *testing.T) {
+	suite.Run(t, new(ConfigTestSuite))
+}
+
+type ConfigTestSuite struct {
+	suite.Suite
+}
+
+const yamlConfig = `
+scopes:
+  - name: config_game
+    aliases:
+      move: Commands.Move
+    registrations:
+      - key: Commands.Move
+        factory: Commands.Move
+      - key: Rules.FuelConsumption
+        factory: Value
+        params:
+          value: 15
+  - name: config_game_child
+    parent: config_game
+    registrations:
+      - key: ErrorHandlers.Log
+        factory: ErrorHandlers.Log
+      - key: ErrorHandlers.Default
+        factory: ErrorHandlers.Repeat
+        params:
+          attempts: 2
+          default: ErrorHandlers.Log
+`
+
+func (s *ConfigTestSuite) TestApplyYAML() {
+	cfg, err := ParseYAML([]byte(yamlConfig))
+	s.Require().NoError(err)
+
+	err = cfg.Apply()
+	s.Require().NoError(err)
+
+	err = ioc.Resolve("Scopes.Current", "config_game_child").(core.Command).Execute()
+	s.Require().NoError(err)
+
+	movable := &mock.MovableMock{}
+	s.Require().IsType(&command.MoveCommand{}, ioc.Resolve("Commands.Move", movable))
+	s.Require().IsType(&command.MoveCommand{}, ioc.Resolve("move", movable))
+	s.Require().Equal(15, ioc.Resolve("Rules.FuelConsumption"))
+
+	queue := &mock.QueueMock{}
+	s.Require().IsType(core.ErrorHandler(nil), ioc.Resolve("ErrorHandlers.Default", queue))
+}
+
+func (s *ConfigTestSuite) TestApplyJSON() {
+	cfg, err := ParseJSON([]byte(`{
+		"scopes": [{
+			"name": "config_json",
+			"registrations": [{"key": "Rules.FuelConsumption", "factory": "Value", "params": {"value": 7}}]
+		}]
+	}`))
+	s.Require().NoError(err)
+
+	err = cfg.Apply()
+	s.Require().NoError(err)
+
+	err = ioc.Resolve("Scopes.Current", "config_json").(core.Command).Execute()
+	s.Require().NoError(err)
+	s.Require().Equal(7, ioc.Resolve("Rules.FuelConsumption"))
+}
+
+func (s *ConfigTestSuite) TestLoad() {
+	path := filepath.Join(s.T().TempDir(), "game.yml")
+	err := os.WriteFile(path, []byte(yamlConfig), 0600)
+	s.Require().NoError(err)
+
+	cfg, err := Load(path)
+	s.Require().NoError(err)
+	s.Require().Len(cfg.Scopes, 2)
+
+	_, err = Load(filepath.Join(s.T().TempDir(), "game.toml"))
+	s.Require().Error(err)
+}
+
+func (s *ConfigTestSuite) TestUnknownFactory() {
+	cfg, err := ParseYAML([]byte(`
+scopes:
+  - name: config_unknown
+    registrations:
+      - key: Commands.Move
+        factory: Commands.Move
+      - key: Commands.Teleport
+        factory: Commands.Teleport
+`))
+	s.Require().NoError(err)
+
+	err = cfg.Apply()
+	s.Require().ErrorIs(err, ErrUnknownFactory)
+	s.Require().Contains(err.Error(), "Commands.Teleport")
+
+	err = ioc.Resolve("Scopes.Current", "config_unknown").(core.Command).Execute()
+	s.Require().ErrorIs(err, ioc.ErrNoSuchScope)
+}
+
+func (s *ConfigTestSuite) TestInvalidParams() {
+	cfg := &Config{
+		Registrations: []Registration{{
+			Key:     "ErrorHandlers.Default",
+			Factory: "ErrorHandlers.Repeat",
+			Params:  map[string]interface{}{"attempts": "many", "default": "ErrorHandlers.Log"},
+		}},
+	}
+	s.Require().ErrorIs(cfg.Validate(), ErrInvalidParam)
+
+	cfg.Registrations[0].Params = map[string]interface{}{"attempts": 2}
+	s.Require().ErrorIs(cfg.Validate(), ErrMissingParam)
+
+	cfg.Registrations[0].Factory = "Commands.Move"
+	s.Require().ErrorIs(cfg.Validate(), ErrUnexpectedParams)
+}
+
+func (s *ConfigTestSuite) TestUnknownParent() {
+	cfg := &Config{
+		Scopes: []ScopeConfig{{
+			Name:   "config_orphan",
+			Parent: "config_no_such_parent",
+		}},
+	}
+	s.Require().ErrorIs(cfg.Apply(), ioc.ErrNoSuchScope)
+}
+
+func (s *ConfigTestSuite) TestUnknownParentRegistersNothing() {
+	cfg := &Config{
+		Scopes: []ScopeConfig{
+			{Name: "config_before_orphan"},
+			{Name: "config_orphan", Parent: "config_no_such_parent"},
+		},
+	}
+	s.Require().ErrorIs(cfg.Apply(), ioc.ErrNoSuchScope)
+
+	err := ioc.Resolve("Scopes.Current", "config_before_orphan").(core.Command).Execute()
+	s.Require().ErrorIs(err, ioc.ErrNoSuchScope)
+}
+
+func (s *ConfigTestSuite) TestAliasCycle() {
+	cfg := &Config{Aliases: map[string]string{"config.self": "config.self"}}
+	s.Require().ErrorIs(cfg.Validate(), ErrAliasCycle)
+
+	cfg = &Config{
+		Aliases: map[string]string{"config.a": "config.b"},
+		Scopes: []ScopeConfig{{
+			Name:    "config_cycle",
+			Aliases: map[string]string{"config.b": "config.a"},
+		}},
+	}
+	s.Require().ErrorIs(cfg.Apply(), ErrAliasCycle)
+
+	cfg.Scopes[0].Registrations = []Registration{{Key: "config.a", Factory: "Commands.Move"}}
+	s.Require().NoError(cfg.Validate())
+}
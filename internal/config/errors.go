@@ -0,0 +1,21 @@
+package config
+
+import "fmt"
+
+var (
+	ErrUnknownFormat = fmt.Errorf("unknown config format")
+
+	ErrUnknownFactory = fmt.Errorf("unknown factory")
+
+	ErrEmptyKey = fmt.Errorf("empty key")
+
+	ErrEmptyScopeName = fmt.Errorf("empty scope name")
+
+	ErrUnexpectedParams = fmt.Errorf("factory takes no params")
+
+	ErrMissingParam = fmt.Errorf("missing param")
+
+	ErrInvalidParam = fmt.Errorf("invalid param")
+
+	ErrAliasCycle = fmt.Errorf("alias cycle")
+)
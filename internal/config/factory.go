@@ -0,0 +1,135 @@
+package config
+
+import (
+	"fmt"
+	"sync"
+
+	"modules/internal/command"
+	"modules/internal/core"
+	"modules/internal/ioc"
+)
+
+type Factory func(params map[string]interface{}) (func(args ...interface{}) interface{}, error)
+
+var factories = sync.Map{}
+
+func RegisterFactory(name string, factory Factory) {
+	factories.Store(name, factory)
+}
+
+func getFactory(name string) (Factory, bool) {
+	factory, ok := factories.Load(name)
+	if !ok {
+		return nil, false
+	}
+
+	return factory.(Factory), true
+}
+
+func init() {
+	RegisterFactory("Value", valueFactory)
+	RegisterFactory("Commands.Move", noParams(func(args ...interface{}) interface{} {
+		return command.NewMoveCommand(args[0].(core.Movable))
+	}))
+	RegisterFactory("Commands.Rotate", noParams(func(args ...interface{}) interface{} {
+		return command.NewRotateCommand(args[0].(core.Rotatable))
+	}))
+	RegisterFactory("Commands.TurnVelocity", noParams(func(args ...interface{}) interface{} {
+		return command.NewTurnVelocityCommand(args[0].(core.MovableRotatable))
+	}))
+	RegisterFactory("Commands.CheckFuel", noParams(func(args ...interface{}) interface{} {
+		return command.NewCheckFuelCommand(args[0].(core.FuelBurnable))
+	}))
+	RegisterFactory("Commands.BurnFuel", noParams(func(args ...interface{}) interface{} {
+		return command.NewBurnFuelCommand(args[0].(core.FuelBurnable))
+	}))
+	RegisterFactory("Commands.MoveWithFuel", noParams(func(args ...interface{}) interface{} {
+		return command.NewMoveWithFuelCommand(args[0].(core.MovableWithFuel))
+	}))
+	RegisterFactory("Commands.RotateWithVelocity", noParams(func(args ...interface{}) interface{} {
+		return command.NewRotateWithVelocityCommand(args[0].(core.Rotatable))
+	}))
+	RegisterFactory("ErrorHandlers.Log", noParams(func(args ...interface{}) interface{} {
+		handler := command.NewLogErrorHandler(args[0].(core.Queue), command.StdLogFunc)
+		return core.ErrorHandler(handler.Handle)
+	}))
+	RegisterFactory("ErrorHandlers.Repeat", repeatErrorHandlerFactory)
+}
+
+func noParams(create func(args ...interface{}) interface{}) Factory {
+	return func(params map[string]interface{}) (func(args ...interface{}) interface{}, error) {
+		if len(params) != 0 {
+			return nil, ErrUnexpectedParams
+		}
+
+		return create, nil
+	}
+}
+
+func valueFactory(params map[string]interface{}) (func(args ...interface{}) interface{}, error) {
+	value, ok := params["value"]
+	if !ok {
+		return nil, fmt.Errorf("%w: value", ErrMissingParam)
+	}
+
+	if number, ok := value.(float64); ok && number == float64(int(number)) {
+		value = int(number)
+	}
+
+	return func(args ...interface{}) interface{} {
+		return value
+	}, nil
+}
+
+// repeatErrorHandlerFactory expects "attempts" and "default" params, where
+// "default" is the key of the error handler used when attempts are exhausted.
+func repeatErrorHandlerFactory(params map[string]interface{}) (func(args ...interface{}) interface{}, error) {
+	attempts, err := intParam(params, "attempts")
+	if err != nil {
+		return nil, err
+	}
+
+	defaultKey, err := stringParam(params, "default")
+	if err != nil {
+		return nil, err
+	}
+
+	return func(args ...interface{}) interface{} {
+		queue := args[0].(core.Queue)
+		defaultHandler := ioc.Resolve(defaultKey, queue).(core.ErrorHandler)
+		handler := command.NewRepeatErrorHandler(queue, attempts, defaultHandler)
+		return core.ErrorHandler(handler.Handle)
+	}, nil
+}
+
+func intParam(params map[string]interface{}, name string) (int, error) {
+	value, ok := params[name]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrMissingParam, name)
+	}
+
+	switch value := value.(type) {
+	case int:
+		return value, nil
+	case float64:
+		if value == float64(int(value)) {
+			return int(value), nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w: %s must be an integer", ErrInvalidParam, name)
+}
+
+func stringParam(params map[string]interface{}, name string) (string, error) {
+	value, ok := params[name]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrMissingParam, name)
+	}
+
+	result, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("%w: %s must be a string", ErrInvalidParam, name)
+	}
+
+	return result, nil
+}
@@ -1,17 +1,31 @@
 package core
 
-import "modules/internal/vector"
+import (
+	"time"
+
+	"modules/internal/vector"
+)
 
 type Command interface {
 	Execute() error
 }
 
+type Undoable interface {
+	Command
+	Undo() error
+}
+
 type ErrorHandler func(command Command, err error)
 
 type Queue interface {
 	Put(Command)
 }
 
+type Object interface {
+	GetProperty(key string) (interface{}, error)
+	SetProperty(key string, value interface{}) error
+}
+
 type Movable interface {
 	GetPosition() (vector.Vector, error)
 	GetVelocity() (vector.Vector, error)
@@ -35,6 +49,15 @@ type FuelBurnable interface {
 	SetFuel(int) error
 }
 
+type Shootable interface {
+	GetAmmo() (int, error)
+	SetAmmo(int) error
+	GetCooldown() (time.Duration, error)
+	GetLastShot() (time.Time, error)
+	SetLastShot(time.Time) error
+	GetProjectileSpeed() (int, error)
+}
+
 type MovableWithFuel interface {
 	Movable
 	FuelBurnable
@@ -45,3 +68,55 @@ type MovableRotatable interface {
 	Rotatable
 	Accelerating
 }
+
+type MovableAccelerating interface {
+	Movable
+	Accelerating
+}
+
+type Thrusting interface {
+	GetThrust() (int, error)
+	GetMaxSpeed() (int, error)
+}
+
+type Thrustable interface {
+	MovableRotatable
+	Thrusting
+}
+
+type ThrustableWithFuel interface {
+	Thrustable
+	FuelBurnable
+	GetThrustConsumption() (int, error)
+}
+
+type Dragging interface {
+	GetVelocity() (vector.Vector, error)
+	SetVelocity(vector.Vector) error
+	GetDrag() (int, error)
+}
+
+type Damageable interface {
+	GetHealth() (int, error)
+	SetHealth(int) error
+}
+
+type Damaging interface {
+	GetDamage() (int, error)
+}
+
+type Collidable interface {
+	GetPosition() (vector.Vector, error)
+	GetRadius() (int, error)
+}
+
+type Shooter interface {
+	Movable
+	Rotatable
+	Shootable
+}
+
+type Controllable interface {
+	GetOwner() (string, error)
+	GetACL() (map[string][]string, error)
+}
@@ -0,0 +1,13 @@
+package game
+
+import "fmt"
+
+var (
+	ErrGameExists = fmt.Errorf("game already exists")
+
+	ErrInvalidGameID = fmt.Errorf("invalid game id")
+
+	ErrManagerClosed = fmt.Errorf("game manager is shut down")
+
+	ErrSubscriberLagging = fmt.Errorf("subscriber fell behind the game events")
+)
@@ -0,0 +1,186 @@
+package game
+
+import (
+	"reflect"
+	"sync"
+
+	"modules/internal/core"
+)
+
+const eventsBufferLength = 256
+
+type Event interface {
+	isEvent()
+}
+
+// StateChanged carries the properties of an object changed by the last
+// executed command. A removed property has a nil value.
+type StateChanged struct {
+	ObjectID   string
+	Properties map[string]interface{}
+	Removed    bool
+}
+
+// CommandFailed is published by the game error handler.
+type CommandFailed struct {
+	Command core.Command
+	Err     error
+}
+
+// Collided is published when the object A moves into an overlap with B.
+type Collided struct {
+	A string
+	B string
+}
+
+func (StateChanged) isEvent() {}
+
+func (CommandFailed) isEvent() {}
+
+func (Collided) isEvent() {}
+
+// events keeps the subscribers and the object state they have been sent.
+// The state is only tracked while there are subscribers, nil means it is
+// stale.
+type events struct {
+	mutex       sync.Mutex
+	subscribers map[*subscriber]struct{}
+	state       map[string]map[string]interface{}
+}
+
+type subscriber struct {
+	events chan Event
+	once   sync.Once
+}
+
+func (s *subscriber) close() {
+	s.once.Do(func() {
+		close(s.events)
+	})
+}
+
+func newEvents() *events {
+	return &events{
+		subscribers: map[*subscriber]struct{}{},
+	}
+}
+
+// Subscribe returns events of the game starting with the current state of
+// every object. A subscriber that does not keep up would miss changes, so
+// its channel is closed instead and it has to subscribe again for the full
+// state. The channel is closed by cancel as well.
+func (g *Game) Subscribe() (<-chan Event, func()) {
+	g.events.mutex.Lock()
+	defer g.events.mutex.Unlock()
+
+	ids := g.objects.IDs()
+	result := &subscriber{events: make(chan Event, eventsBufferLength+len(ids))}
+	state := g.events.state
+	if state == nil {
+		state = make(map[string]map[string]interface{}, len(ids))
+	}
+
+	for _, id := range ids {
+		o, err := g.objects.Get(id)
+		if err != nil {
+			continue
+		}
+
+		properties := o.Properties()
+		result.events <- StateChanged{ObjectID: id, Properties: properties}
+		if g.events.state == nil {
+			state[id] = properties
+		}
+	}
+
+	g.events.state = state
+	g.events.subscribers[result] = struct{}{}
+
+	cancel := func() {
+		g.events.mutex.Lock()
+		delete(g.events.subscribers, result)
+		g.events.mutex.Unlock()
+		result.close()
+	}
+
+	return result.events, cancel
+}
+
+func (g *Game) publish(event Event) {
+	g.events.mutex.Lock()
+	defer g.events.mutex.Unlock()
+
+	g.publishLocked(event)
+}
+
+// publishLocked disconnects the subscribers whose buffer is full.
+func (g *Game) publishLocked(event Event) {
+	for s := range g.events.subscribers {
+		select {
+		case s.events <- event:
+		default:
+			delete(g.events.subscribers, s)
+			s.close()
+		}
+	}
+}
+
+// trackState runs in the listener goroutine after every command and
+// publishes the properties changed since the previous command. Comparing
+// every object is skipped while nobody is subscribed, Subscribe then sends
+// the full state.
+func (g *Game) trackState(core.Command, error) {
+	g.events.mutex.Lock()
+	defer g.events.mutex.Unlock()
+
+	if len(g.events.subscribers) == 0 {
+		g.events.state = nil
+		return
+	}
+	if g.events.state == nil {
+		g.events.state = map[string]map[string]interface{}{}
+	}
+
+	ids := g.objects.IDs()
+	seen := make(map[string]struct{}, len(ids))
+
+	for _, id := range ids {
+		o, err := g.objects.Get(id)
+		if err != nil {
+			continue
+		}
+		seen[id] = struct{}{}
+
+		properties := o.Properties()
+		changed := diff(g.events.state[id], properties)
+		g.events.state[id] = properties
+		if len(changed) != 0 {
+			g.publishLocked(StateChanged{ObjectID: id, Properties: changed})
+		}
+	}
+
+	for id := range g.events.state {
+		if _, ok := seen[id]; !ok {
+			delete(g.events.state, id)
+			g.publishLocked(StateChanged{ObjectID: id, Removed: true})
+		}
+	}
+}
+
+func diff(previous, current map[string]interface{}) map[string]interface{} {
+	result := map[string]interface{}{}
+	for key, value := range current {
+		old, ok := previous[key]
+		if !ok || !reflect.DeepEqual(old, value) {
+			result[key] = value
+		}
+	}
+
+	for key := range previous {
+		if _, ok := current[key]; !ok {
+			result[key] = nil
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,210 @@
+package game
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"modules/internal/collision"
+	"modules/internal/interpreter"
+	"modules/internal/object"
+	"modules/internal/vector"
+)
+
+func TestEvents(t *testing.T) {
+	suite.Run(t, new(EventsTestSuite))
+}
+
+type EventsTestSuite struct {
+	suite.Suite
+
+	manager *Manager
+	game    *Game
+}
+
+func (s *EventsTestSuite) SetupTest() {
+	registerOperations.Do(func() {
+		s.Require().NoError(interpreter.RegisterOperations())
+		s.Require().NoError(collision.RegisterRules())
+	})
+
+	objects := object.NewRegistry()
+	s.Require().NoError(objects.Add("548", object.New(map[string]interface{}{
+		object.Position: vector.New([]int{12, 5}),
+		object.Velocity: vector.New([]int{-7, 3}),
+	})))
+
+	var err error
+	s.manager = NewManager(10)
+	s.game, err = s.manager.Create("events_game", objects)
+	s.Require().NoError(err)
+}
+
+func (s *EventsTestSuite) TearDownTest() {
+	s.Require().NoError(s.manager.Shutdown(context.Background()))
+}
+
+func (s *EventsTestSuite) TestStateChanged() {
+	events, cancel := s.game.Subscribe()
+	defer cancel()
+
+	s.Require().Equal(StateChanged{
+		ObjectID: "548",
+		Properties: map[string]interface{}{
+			object.Position: vector.New([]int{12, 5}),
+			object.Velocity: vector.New([]int{-7, 3}),
+		},
+	}, <-events)
+
+	err := s.manager.Execute(context.Background(), interpreter.Message{
+		GameID:      "events_game",
+		ObjectID:    "548",
+		OperationID: "move",
+	})
+	s.Require().NoError(err)
+
+	s.Require().Equal(StateChanged{
+		ObjectID: "548",
+		Properties: map[string]interface{}{
+			object.Position: vector.New([]int{5, 8}),
+		},
+	}, <-events)
+
+	s.Require().NoError(s.game.Objects().Remove("548"))
+	s.Require().NoError(s.manager.Stop("events_game", false))
+	<-s.game.Done()
+
+	s.Require().Equal(StateChanged{ObjectID: "548", Removed: true}, <-events)
+}
+
+func (s *EventsTestSuite) TestCommandFailed() {
+	events, cancel := s.game.Subscribe()
+	<-events
+
+	message := interpreter.Message{
+		GameID:      "events_game",
+		ObjectID:    "548",
+		OperationID: "move_with_fuel",
+	}
+	err := s.manager.Submit(message)
+	s.Require().NoError(err)
+
+	event := (<-events).(CommandFailed)
+	s.Require().ErrorIs(event.Err, object.ErrNoProperty)
+	s.Require().Equal(message, event.Command.(*interpreter.OrderCommand).Message())
+
+	cancel()
+	cancel()
+	_, ok := <-events
+	s.Require().False(ok)
+}
+
+func (s *EventsTestSuite) TestCollided() {
+	objects := object.NewRegistry()
+	s.Require().NoError(objects.Add("548", object.New(map[string]interface{}{
+		object.Position: vector.New([]int{12, 5}),
+		object.Velocity: vector.New([]int{-7, 3}),
+		object.Radius:   2,
+	})))
+	s.Require().NoError(objects.Add("549", object.New(map[string]interface{}{
+		object.Position: vector.New([]int{3, 8}),
+	})))
+	g, err := s.manager.Create("events_collision", objects)
+	s.Require().NoError(err)
+	s.Require().Equal(2, g.Collisions().Len())
+
+	events, cancel := g.Subscribe()
+	defer cancel()
+	<-events
+	<-events
+
+	err = s.manager.Execute(context.Background(), interpreter.Message{
+		GameID:      "events_collision",
+		ObjectID:    "548",
+		OperationID: "move",
+	})
+	s.Require().NoError(err)
+	s.Require().Equal(Collided{A: "548", B: "549"}, <-events)
+	s.Require().Equal("548", (<-events).(StateChanged).ObjectID)
+}
+
+func (s *EventsTestSuite) TestCollisionResponse() {
+	objects := object.NewRegistry()
+	ship := object.New(map[string]interface{}{
+		object.Position: vector.New([]int{12, 5}),
+		object.Velocity: vector.New([]int{-7, 3}),
+		object.Radius:   2,
+		object.Health:   3,
+	})
+	s.Require().NoError(objects.Add("548", ship))
+	s.Require().NoError(objects.Add("shot", object.New(map[string]interface{}{
+		object.Kind:     object.KindProjectile,
+		object.Position: vector.New([]int{4, 8}),
+		object.Damage:   2,
+	})))
+	g, err := s.manager.Create("events_collision_response", objects)
+	s.Require().NoError(err)
+
+	message := interpreter.Message{
+		GameID:      "events_collision_response",
+		ObjectID:    "548",
+		OperationID: "move",
+	}
+	s.Require().NoError(s.manager.Execute(context.Background(), message))
+
+	// the response is queued before the next order
+	message.OperationID = "end_move"
+	s.Require().Error(s.manager.Execute(context.Background(), message))
+	s.Require().Equal(1, ship.Properties()[object.Health])
+	s.Require().Equal([]string{"548"}, objects.IDs())
+	s.Require().Equal(1, g.Collisions().Len())
+}
+
+func (s *EventsTestSuite) TestLaggingSubscriber() {
+	events, cancel := s.game.Subscribe()
+	defer cancel()
+
+	for i := 0; i <= eventsBufferLength; i++ {
+		s.game.publish(Collided{A: "548", B: "549"})
+	}
+
+	s.Require().Equal("548", (<-events).(StateChanged).ObjectID)
+	for i := 0; i < eventsBufferLength; i++ {
+		s.Require().Equal(Collided{A: "548", B: "549"}, <-events)
+	}
+	_, ok := <-events
+	s.Require().False(ok)
+
+	events, cancel = s.game.Subscribe()
+	defer cancel()
+	s.Require().Equal(StateChanged{
+		ObjectID: "548",
+		Properties: map[string]interface{}{
+			object.Position: vector.New([]int{12, 5}),
+			object.Velocity: vector.New([]int{-7, 3}),
+		},
+	}, <-events)
+}
+
+func (s *EventsTestSuite) TestNoSubscribers() {
+	message := interpreter.Message{
+		GameID:      "events_game",
+		ObjectID:    "548",
+		OperationID: "move",
+	}
+	s.Require().NoError(s.manager.Execute(context.Background(), message))
+	s.game.events.mutex.Lock()
+	s.Require().Nil(s.game.events.state)
+	s.game.events.mutex.Unlock()
+
+	events, cancel := s.game.Subscribe()
+	defer cancel()
+	s.Require().Equal(vector.New([]int{5, 8}), (<-events).(StateChanged).Properties[object.Position])
+
+	s.Require().NoError(s.manager.Execute(context.Background(), message))
+	s.Require().Equal(StateChanged{
+		ObjectID:   "548",
+		Properties: map[string]interface{}{object.Position: vector.New([]int{-2, 11})},
+	}, <-events)
+}
@@ -0,0 +1,471 @@
+package game
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"modules/internal/clock"
+	"modules/internal/collision"
+	"modules/internal/command"
+	"modules/internal/core"
+	"modules/internal/interpreter"
+	"modules/internal/ioc"
+	"modules/internal/journal"
+	"modules/internal/object"
+	"modules/internal/queue"
+	"modules/internal/repository"
+	"modules/internal/scheduler"
+	"modules/internal/snapshot"
+	"modules/internal/vector"
+	"modules/internal/world"
+)
+
+type Game struct {
+	id         string
+	objects    *object.Registry
+	listener   *queue.Listener
+	scheduler  *scheduler.Scheduler
+	tokens     *command.Tokens
+	collisions *collision.Detector
+	journal    *journal.Journal
+	events     *events
+	finished   chan struct{}
+
+	// removed keeps the objects a replay has removed by id, nil for a game
+	// that is not replayed.
+	removed map[string]*object.Object
+
+	// releaseScope releases the game scope of the listener goroutine.
+	releaseScope core.Command
+
+	clock            clock.Clock
+	snapshotPath     string
+	repository       repository.Repository
+	snapshotInterval time.Duration
+}
+
+func (g *Game) ID() string {
+	return g.id
+}
+
+func (g *Game) Objects() *object.Registry {
+	return g.objects
+}
+
+func (g *Game) Queue() core.Queue {
+	return g.listener.GetQueue()
+}
+
+func (g *Game) Scheduler() *scheduler.Scheduler {
+	return g.scheduler
+}
+
+func (g *Game) Collisions() *collision.Detector {
+	return g.collisions
+}
+
+func (g *Game) Done() <-chan struct{} {
+	return g.listener.Done()
+}
+
+// newGame creates the game scope as a child of the default scope and
+// registers "Game.ID", "Game.Objects", "Game.Queue", "Game.Scheduler",
+// "Game.Tokens", "Game.Collisions", "Game.Codec", "Game.RemoveObject" and
+// "Game.Map", if "Maps" resolves one for the game id, in it. The listener
+// switches to the game scope before executing any other command. The
+// scheduler ticks every "Game.TickInterval" of the "Clock" and the collision
+// grid has cells of "Game.CollisionCellSize" if registered. The executed
+// commands are journaled to the writer "Journals" resolves for the game id,
+// if any, with a checkpoint every "Game.JournalCheckpointInterval" commands.
+// A snapshot of the objects is saved to the path "Snapshots" resolves for
+// the game id, if any, and to the "Repository", if registered, every
+// "Game.SnapshotInterval" and once the game stops.
+func newGame(id string, objects *object.Registry, bufferLength int) (*Game, error) {
+	result := &Game{
+		id:       id,
+		objects:  objects,
+		listener: queue.NewListener(bufferLength),
+		events:   newEvents(),
+		finished: make(chan struct{}),
+	}
+	result.listener.SetAfterExecute(result.afterExecute)
+
+	var inherit core.Command
+	errChan := make(chan error, 1)
+	go func() {
+		defer ioc.Resolve("Scopes.Release").(core.Command).Execute()
+		err := result.registerScope()
+		inherit = ioc.Resolve("Scopes.Inherit").(core.Command)
+		errChan <- err
+	}()
+
+	err := <-errChan
+	if err != nil {
+		_ = result.dropScope()
+		return nil, err
+	}
+
+	result.listener.GetQueue().Put(&enterScopeCommand{game: result, inherit: inherit})
+	err = result.listener.StartCommand().Execute()
+	if err != nil {
+		return nil, err
+	}
+	result.scheduler.Start(result.listener.Done())
+
+	if result.snapshotPath != "" || result.repository != nil {
+		go result.takeSnapshots()
+	}
+	go result.finish()
+
+	return result, nil
+}
+
+// Finished is closed once the game is done and has closed its journal and
+// saved its last snapshots.
+func (g *Game) Finished() <-chan struct{} {
+	return g.finished
+}
+
+// finish closes the journal and saves the last snapshots once the game
+// stops. Nothing changes the objects any more then, so the snapshots are
+// saved right away.
+func (g *Game) finish() {
+	defer close(g.finished)
+	<-g.Done()
+
+	if g.releaseScope != nil {
+		_ = g.releaseScope.Execute()
+	}
+
+	if g.journal != nil {
+		err := g.journal.Close()
+		if err != nil {
+			log.Printf("game %q journal: %s", g.id, err)
+		}
+	}
+
+	for _, c := range g.snapshotCommands() {
+		err := c.Execute()
+		if err != nil {
+			log.Printf("game %q snapshot: %s", g.id, err)
+		}
+	}
+}
+
+func (g *Game) afterExecute(executed core.Command, err error) {
+	if g.journal != nil && journaled(executed) {
+		journalErr := g.journal.Record(executed, err)
+		if journalErr != nil {
+			log.Printf("game %q journal: %s", g.id, journalErr)
+		}
+	}
+	g.trackState(executed, err)
+}
+
+// journaled tells if the journal records the command. The commands of the
+// game itself that do not change the objects are left out.
+func journaled(executed core.Command) bool {
+	switch executed.(type) {
+	case *enterScopeCommand, *failedCommand, command.LogCommand, *queue.ListenerSoftStopCommand, *snapshot.TakeCommand, *repository.SaveCommand:
+		return false
+	default:
+		return true
+	}
+}
+
+func (g *Game) registerScope() error {
+	err := ioc.Resolve("Scopes.New", g.id).(core.Command).Execute()
+	if err != nil {
+		return err
+	}
+
+	interval, ok := ioc.Resolve("Game.TickInterval").(time.Duration)
+	if !ok {
+		interval = scheduler.DefaultInterval
+	}
+	c, ok := ioc.Resolve("Clock").(clock.Clock)
+	if !ok {
+		c = clock.NewReal()
+	}
+	g.clock = c
+	g.scheduler = scheduler.NewScheduler(g.listener.GetQueue(), c, interval)
+	g.tokens = command.NewTokens()
+
+	err = g.createDetector()
+	if err != nil {
+		return err
+	}
+
+	codec := g.newCodec(g.listener.GetQueue())
+	if writer, ok := ioc.Resolve("Journals", g.id).(io.Writer); ok {
+		interval, _ := ioc.Resolve("Game.JournalCheckpointInterval").(int)
+		g.journal, err = journal.New(writer, g.objects, codec, interval)
+		if err != nil {
+			return err
+		}
+	}
+
+	g.snapshotPath, _ = ioc.Resolve("Snapshots", g.id).(string)
+	g.repository, _ = ioc.Resolve("Repository").(repository.Repository)
+	g.snapshotInterval, ok = ioc.Resolve("Game.SnapshotInterval").(time.Duration)
+	if !ok {
+		g.snapshotInterval = snapshot.DefaultInterval
+	}
+
+	registrations := []registration{
+		{"Game.ID", g.id},
+		{"Game.Objects", g.objects},
+		{"Game.Queue", g.listener.GetQueue()},
+		{"Game.Scheduler", g.scheduler},
+		{"Game.Tokens", g.tokens},
+		{"Game.Collisions", g.collisions},
+		{"Game.Codec", codec},
+	}
+
+	if worldMap, ok := ioc.Resolve("Maps", g.id).(*world.Map); ok {
+		registrations = append(registrations, registration{"Game.Map", worldMap})
+	}
+
+	for _, r := range registrations {
+		value := r.value
+		err = ioc.Resolve("IoC.Register", r.key, func(params ...interface{}) interface{} {
+			return value
+		}).(core.Command).Execute()
+		if err != nil {
+			return err
+		}
+	}
+
+	err = ioc.Resolve("IoC.Register", "Game.RemoveObject", func(params ...interface{}) interface{} {
+		return g.removeObject(params[0].(core.Object))
+	}).(core.Command).Execute()
+	if err != nil {
+		return err
+	}
+
+	errorHandler, ok := ioc.Resolve("ErrorHandlers.Default", g.listener.GetQueue()).(core.ErrorHandler)
+	if !ok {
+		errorHandler = command.NewLogErrorHandler(g.listener.GetQueue(), command.StdLogFunc).Handle
+	}
+	handle := func(command core.Command, err error) {
+		g.publish(CommandFailed{Command: command, Err: err})
+		errorHandler(command, err)
+	}
+	g.listener.SetErrorHandler(handle)
+	g.scheduler.SetErrorHandler(handle)
+
+	return nil
+}
+
+// takeSnapshots puts the snapshot commands into the game queue on every
+// snapshot interval until the game stops.
+func (g *Game) takeSnapshots() {
+	ticker := g.clock.NewTicker(g.snapshotInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C():
+			for _, c := range g.snapshotCommands() {
+				g.Queue().Put(c)
+			}
+		case <-g.Done():
+			return
+		}
+	}
+}
+
+func (g *Game) snapshotCommands() []core.Command {
+	var result []core.Command
+	if g.snapshotPath != "" {
+		result = append(result, snapshot.NewTakeCommand(g.id, g.objects, g.clock, g.snapshotPath))
+	}
+	if g.repository != nil {
+		result = append(result, repository.NewSaveCommand(g.repository, g.id, g.objects))
+	}
+	return result
+}
+
+type registration struct {
+	key   string
+	value interface{}
+}
+
+// createDetector puts the objects having a position into the collision grid.
+// The cells are made larger than "Game.CollisionCellSize" if the largest
+// object needs it.
+func (g *Game) createDetector() error {
+	cellSize, ok := ioc.Resolve("Game.CollisionCellSize").(int)
+	if !ok {
+		cellSize = collision.DefaultCellSize
+	}
+
+	type placed struct {
+		id       string
+		object   *object.Object
+		position vector.Vector
+		radius   int
+	}
+	var objects []placed
+	for _, id := range g.objects.IDs() {
+		o, err := g.objects.Get(id)
+		if err != nil {
+			continue
+		}
+
+		adapter := object.NewAdapter(o)
+		position, err := adapter.GetPosition()
+		if err != nil {
+			continue
+		}
+
+		radius, err := adapter.GetRadius()
+		if err != nil {
+			return fmt.Errorf("object %q: %w", id, err)
+		}
+
+		objects = append(objects, placed{id: id, object: o, position: position, radius: radius})
+		cellSize = max(cellSize, collision.CellSizeFor(radius))
+	}
+
+	detector, err := collision.NewDetector(cellSize)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range objects {
+		_, err = detector.Update(p.object, p.position, p.radius)
+		if err != nil {
+			return fmt.Errorf("object %q: %w", p.id, err)
+		}
+	}
+
+	detector.SetHandler(g.collided)
+	g.collisions = detector
+	return nil
+}
+
+// collided publishes collisions of the game objects, which the detector
+// keys by the objects themselves, and puts the responses resolved by the
+// kinds of the objects into the queue.
+func (g *Game) collided(c collision.Collision) {
+	a, okA := c.A.(*object.Object)
+	b, okB := c.B.(*object.Object)
+	if !okA || !okB {
+		return
+	}
+
+	idA, okA := g.objects.ID(a)
+	idB, okB := g.objects.ID(b)
+	if !okA || !okB {
+		return
+	}
+
+	g.publish(Collided{A: idA, B: idB})
+
+	response, err := collision.Response(a, b)
+	if err != nil {
+		response = &failedCommand{err: err}
+	}
+	if response != nil {
+		command.PutBack(g.listener.GetQueue(), response)
+	}
+}
+
+// newCodec creates the codec of the game, which knows every command the
+// listener executes and the journal records. The decoded commands put
+// commands into the queue.
+func (g *Game) newCodec(queue core.Queue) *command.Codec {
+	codec := command.NewCodec(g.objects)
+	codec.SetRemoved(func(id string) (*object.Object, bool) {
+		o, ok := g.removed[id]
+		return o, ok
+	})
+	command.RegisterRepeating(codec, queue, g.tokens)
+	scheduler.RegisterCodec(codec, g.scheduler)
+	collision.RegisterCodec(codec, g.collisions, g.removeObject)
+	interpreter.RegisterCodec(codec, g.objects, g.scheduler)
+	codec.Register("remove_object", &removeObjectCommand{}, func(c *command.Codec, cmd core.Command) (command.DTO, error) {
+		id, err := c.ObjectID(cmd.(*removeObjectCommand).object)
+		return command.DTO{ObjectID: id}, err
+	}, func(c *command.Codec, dto command.DTO) (core.Command, error) {
+		target, err := c.Object(dto)
+		if err != nil {
+			return nil, err
+		}
+		return g.removeObject(target.Object()), nil
+	})
+	return codec
+}
+
+func (g *Game) removeObject(target core.Object) core.Command {
+	o, _ := target.(*object.Object)
+	return &removeObjectCommand{game: g, object: o}
+}
+
+// removeObjectCommand removes the object from the game objects and the
+// collision grid and ends its movements. Removing an object that is gone
+// already does nothing, e.g. for a projectile hitting two ships at once.
+type removeObjectCommand struct {
+	game   *Game
+	object *object.Object
+}
+
+func (c *removeObjectCommand) Execute() error {
+	g := c.game
+	id, ok := g.objects.ID(c.object)
+	if !ok {
+		return nil
+	}
+
+	err := g.objects.Remove(id)
+	if err != nil {
+		return err
+	}
+	if g.removed != nil {
+		g.removed[id] = c.object
+	}
+
+	g.collisions.Remove(c.object)
+	_ = command.NewEndMoveCommand(g.tokens, c.object).Execute()
+	_ = scheduler.NewStopMoveCommand(g.scheduler, c.object).Execute()
+	return nil
+}
+
+// failedCommand hands the error to the error handler through the queue.
+type failedCommand struct {
+	err error
+}
+
+func (c *failedCommand) Execute() error {
+	return c.err
+}
+
+// enterScopeCommand switches the goroutine executing it, the listener, to the
+// game scope, which "Scopes.Inherit" resolved in the game scope makes current
+// even once the name of the scope is dropped. The command releasing the scope
+// once the listener is done is resolved on execution since it is bound to the
+// resolving goroutine.
+type enterScopeCommand struct {
+	game    *Game
+	inherit core.Command
+}
+
+func (c *enterScopeCommand) Execute() error {
+	err := c.inherit.Execute()
+	if err != nil {
+		return err
+	}
+
+	c.game.releaseScope = ioc.Resolve("Scopes.Release").(core.Command)
+	return nil
+}
+
+// dropScope forgets the name of the game scope, so that a game created later
+// with the same id starts with a new scope. The listener keeps the scope until
+// it is done.
+func (g *Game) dropScope() error {
+	return ioc.Resolve("Scopes.Delete", g.id).(core.Command).Execute()
+}
@@ -0,0 +1,268 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sync"
+
+	"modules/internal/core"
+	"modules/internal/interpreter"
+	"modules/internal/object"
+	"modules/internal/repository"
+	"modules/internal/snapshot"
+)
+
+type order struct {
+	command core.Command
+	errChan chan error
+}
+
+// OrderWrapper decorates the command interpreting a player message, e.g. to
+// check the player may send it.
+type OrderWrapper func(message interpreter.Message, command core.Command) core.Command
+
+type Manager struct {
+	mutex        sync.RWMutex
+	games        map[string]*Game
+	closed       bool
+	bufferLength int
+	orders       chan order
+	done         chan struct{}
+	wrap         OrderWrapper
+}
+
+// NewManager starts a goroutine routing player orders into game queues. It
+// is stopped by Shutdown.
+func NewManager(bufferLength int) *Manager {
+	result := &Manager{
+		games:        map[string]*Game{},
+		bufferLength: bufferLength,
+		orders:       make(chan order),
+		done:         make(chan struct{}),
+	}
+	go result.run()
+
+	return result
+}
+
+// run executes the orders one by one. They never wait for a busy game, see
+// InterpretCommand, so one game cannot hold up the orders of the others.
+func (m *Manager) run() {
+	for {
+		select {
+		case o := <-m.orders:
+			o.errChan <- o.command.Execute()
+		case <-m.done:
+			return
+		}
+	}
+}
+
+// SetOrderWrapper makes the manager wrap the commands of all orders
+// submitted afterwards. Wrapped commands are executed before anything is put
+// into a game queue.
+func (m *Manager) SetOrderWrapper(wrap OrderWrapper) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.wrap = wrap
+}
+
+// idPattern keeps game ids safe to use in file names.
+var idPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// ValidateID fails with ErrInvalidGameID unless the id is made of 1 to 64
+// letters, digits, underscores and hyphens.
+func ValidateID(id string) error {
+	if !idPattern.MatchString(id) {
+		return fmt.Errorf("%w: %q", ErrInvalidGameID, id)
+	}
+
+	return nil
+}
+
+// Create starts a game with the objects. The id has to pass ValidateID.
+func (m *Manager) Create(id string, objects *object.Registry) (*Game, error) {
+	err := ValidateID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.closed {
+		return nil, ErrManagerClosed
+	}
+
+	if _, ok := m.games[id]; ok {
+		return nil, fmt.Errorf("%w: %q", ErrGameExists, id)
+	}
+
+	result, err := newGame(id, objects, m.bufferLength)
+	if err != nil {
+		return nil, err
+	}
+
+	m.games[id] = result
+	return result, nil
+}
+
+func (m *Manager) Get(id string) (*Game, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	result, ok := m.games[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", interpreter.ErrUnknownGame, id)
+	}
+
+	return result, nil
+}
+
+func (m *Manager) Submit(message interpreter.Message) error {
+	return m.route(message, interpreter.NewInterpretCommand(message))
+}
+
+// Execute submits the order and waits until the game executes it, returning
+// the error of the resulting command.
+func (m *Manager) Execute(ctx context.Context, message interpreter.Message) error {
+	resultChan := make(chan error, 1)
+	command := interpreter.NewInterpretCommandWithReport(message, func(err error) {
+		resultChan <- err
+	})
+
+	err := m.route(message, command)
+	if err != nil {
+		return err
+	}
+
+	select {
+	case err = <-resultChan:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// route hands the command to the routing goroutine. The lock is released
+// before that, so waiting for the goroutine never blocks Create, Stop or
+// Shutdown.
+func (m *Manager) route(message interpreter.Message, command core.Command) error {
+	m.mutex.RLock()
+	_, ok := m.games[message.GameID]
+	wrap := m.wrap
+	m.mutex.RUnlock()
+
+	if !ok {
+		return fmt.Errorf("%w: %q", interpreter.ErrUnknownGame, message.GameID)
+	}
+
+	if wrap != nil {
+		command = wrap(message, command)
+	}
+
+	errChan := make(chan error, 1)
+	select {
+	case m.orders <- order{command: command, errChan: errChan}:
+	case <-m.done:
+		return ErrManagerClosed
+	}
+
+	return <-errChan
+}
+
+// Restore creates the game of the snapshot with the objects it recorded.
+// Movements are not part of a snapshot, so the objects of the restored game
+// stand still until they are started again.
+func (m *Manager) Restore(s snapshot.Snapshot) (*Game, error) {
+	objects, err := s.Restore()
+	if err != nil {
+		return nil, err
+	}
+
+	return m.Create(s.GameID, objects)
+}
+
+// Load creates the game with the objects stored in the repository. Like
+// Restore, it does not start the movements the game had.
+func (m *Manager) Load(r repository.Repository, id string) (*Game, error) {
+	var objects *object.Registry
+	err := r.View(func(tx repository.Tx) (err error) {
+		objects, err = tx.LoadGame(id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return m.Create(id, objects)
+}
+
+// Stop removes the game from the manager. A soft stop lets the game execute
+// the commands already in its queue, a hard stop drops them.
+func (m *Manager) Stop(id string, hard bool) error {
+	m.mutex.Lock()
+	g, ok := m.games[id]
+	var err error
+	if ok {
+		delete(m.games, id)
+		err = g.dropScope()
+	}
+	m.mutex.Unlock()
+
+	if !ok {
+		return fmt.Errorf("%w: %q", interpreter.ErrUnknownGame, id)
+	}
+
+	return errors.Join(err, g.stop(hard))
+}
+
+// stop never waits for room in the queue of a busy game: the soft stop is
+// put from a goroutine, which a hard stop releases too.
+func (g *Game) stop(hard bool) error {
+	if hard {
+		return g.listener.HardStopCommand().Execute()
+	}
+
+	go g.Queue().Put(g.listener.SoftStopCommand())
+	return nil
+}
+
+// Shutdown soft stops all games and waits for them to finish, see
+// Game.Finished. Games still running when ctx is done are stopped hard.
+func (m *Manager) Shutdown(ctx context.Context) error {
+	m.mutex.Lock()
+	if m.closed {
+		m.mutex.Unlock()
+		return ErrManagerClosed
+	}
+
+	games := m.games
+	m.games = map[string]*Game{}
+	m.closed = true
+	close(m.done)
+	m.mutex.Unlock()
+
+	for _, g := range games {
+		err := errors.Join(g.dropScope(), g.stop(false))
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, g := range games {
+		select {
+		case <-g.Finished():
+		case <-ctx.Done():
+			for _, g := range games {
+				_ = g.stop(true)
+			}
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
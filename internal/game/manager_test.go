@@ -0,0 +1,714 @@
+package game
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"modules/internal/clock"
+	"modules/internal/collision"
+	"modules/internal/command"
+	"modules/internal/core"
+	"modules/internal/interpreter"
+	"modules/internal/ioc"
+	"modules/internal/journal"
+	"modules/internal/mock"
+	"modules/internal/object"
+	"modules/internal/repository"
+	"modules/internal/scheduler"
+	"modules/internal/snapshot"
+	"modules/internal/vector"
+	"modules/internal/world"
+)
+
+var registerOperations sync.Once
+
+func TestManager(t *testing.T) {
+	suite.Run(t, new(ManagerTestSuite))
+}
+
+type ManagerTestSuite struct {
+	suite.Suite
+
+	manager *Manager
+	ship    *object.Object
+	objects *object.Registry
+}
+
+func (s *ManagerTestSuite) SetupTest() {
+	registerOperations.Do(func() {
+		s.Require().NoError(interpreter.RegisterOperations())
+		s.Require().NoError(collision.RegisterRules())
+	})
+
+	s.manager = NewManager(10)
+	s.ship = object.New(map[string]interface{}{
+		object.Position: vector.New([]int{12, 5}),
+		object.Velocity: vector.New([]int{-7, 3}),
+	})
+	s.objects = object.NewRegistry()
+	s.Require().NoError(s.objects.Add("548", s.ship))
+}
+
+func (s *ManagerTestSuite) TearDownTest() {
+	_ = s.manager.Shutdown(context.Background())
+}
+
+func (s *ManagerTestSuite) TestSubmit() {
+	g, err := s.manager.Create("manager_submit", s.objects)
+	s.Require().NoError(err)
+
+	err = s.manager.Submit(interpreter.Message{
+		GameID:      "manager_submit",
+		ObjectID:    "548",
+		OperationID: "move",
+	})
+	s.Require().NoError(err)
+
+	s.Require().NoError(s.manager.Stop("manager_submit", false))
+	<-g.Done()
+
+	position, err := s.ship.GetProperty(object.Position)
+	s.Require().NoError(err)
+	s.Require().Equal(vector.New([]int{5, 8}), position)
+}
+
+func (s *ManagerTestSuite) TestGameScope() {
+	g, err := s.manager.Create("manager_scope", s.objects)
+	s.Require().NoError(err)
+
+	var gameID, stale interface{}
+	command := mock.CommandMock{}
+	command.On("Execute").Return(func() error {
+		gameID = ioc.Resolve("Game.ID")
+		stale = ioc.Resolve("manager_scope_value")
+		return ioc.Resolve("IoC.Register", "manager_scope_value", func(params ...interface{}) interface{} {
+			return 1
+		}).(core.Command).Execute()
+	})
+	g.Queue().Put(&command)
+
+	s.Require().NoError(s.manager.Stop("manager_scope", false))
+	<-g.Done()
+	s.Require().Equal("manager_scope", gameID)
+	s.Require().Nil(stale)
+
+	// a game created again with the id starts with a new scope
+	g, err = s.manager.Create("manager_scope", object.NewRegistry())
+	s.Require().NoError(err)
+	g.Queue().Put(&command)
+	s.Require().NoError(s.manager.Stop("manager_scope", false))
+	<-g.Done()
+	s.Require().Equal("manager_scope", gameID)
+	s.Require().Nil(stale)
+}
+
+func (s *ManagerTestSuite) TestUnknownGame() {
+	err := s.manager.Submit(interpreter.Message{GameID: "manager_unknown"})
+	s.Require().ErrorIs(err, interpreter.ErrUnknownGame)
+
+	_, err = s.manager.Get("manager_unknown")
+	s.Require().ErrorIs(err, interpreter.ErrUnknownGame)
+
+	err = s.manager.Stop("manager_unknown", true)
+	s.Require().ErrorIs(err, interpreter.ErrUnknownGame)
+}
+
+func (s *ManagerTestSuite) TestDuplicate() {
+	_, err := s.manager.Create("manager_duplicate", s.objects)
+	s.Require().NoError(err)
+
+	_, err = s.manager.Create("manager_duplicate", s.objects)
+	s.Require().ErrorIs(err, ErrGameExists)
+}
+
+func (s *ManagerTestSuite) TestInvalidID() {
+	for _, id := range []string{"", "../../x", "a/b", "a b", strings.Repeat("x", 65)} {
+		_, err := s.manager.Create(id, s.objects)
+		s.Require().ErrorIs(err, ErrInvalidGameID, id)
+	}
+}
+
+func (s *ManagerTestSuite) TestHardStop() {
+	g, err := s.manager.Create("manager_hard_stop", s.objects)
+	s.Require().NoError(err)
+
+	blocker := make(chan struct{})
+	command := mock.CommandMock{}
+	command.On("Execute").Return(func() error {
+		<-blocker
+		return nil
+	})
+	g.Queue().Put(&command)
+
+	err = s.manager.Submit(interpreter.Message{
+		GameID:      "manager_hard_stop",
+		ObjectID:    "548",
+		OperationID: "move",
+	})
+	s.Require().NoError(err)
+
+	s.Require().NoError(s.manager.Stop("manager_hard_stop", true))
+	close(blocker)
+	<-g.Done()
+
+	position, err := s.ship.GetProperty(object.Position)
+	s.Require().NoError(err)
+	s.Require().Equal(vector.New([]int{12, 5}), position)
+}
+
+func (s *ManagerTestSuite) TestFullQueue() {
+	busy, err := s.manager.Create("manager_busy", s.objects)
+	s.Require().NoError(err)
+	_, err = s.manager.Create("manager_idle", object.NewRegistry())
+	s.Require().NoError(err)
+
+	blocker := make(chan struct{})
+	started := make(chan struct{})
+	command := mock.CommandMock{}
+	command.On("Execute").Return(func() error {
+		close(started)
+		<-blocker
+		return nil
+	}).Once()
+	busy.Queue().Put(&command)
+	<-started
+	noop := mock.CommandMock{}
+	noop.On("Execute").Return(nil)
+	for i := 0; i < 10; i++ {
+		busy.Queue().Put(&noop)
+	}
+
+	message := interpreter.Message{
+		GameID:      "manager_busy",
+		ObjectID:    "548",
+		OperationID: "move",
+	}
+	s.Require().ErrorIs(s.manager.Submit(message), interpreter.ErrQueueFull)
+
+	message.GameID = "manager_idle"
+	s.Require().ErrorIs(s.manager.Submit(message), object.ErrUnknownObject)
+	_, err = s.manager.Create("manager_other", object.NewRegistry())
+	s.Require().NoError(err)
+
+	close(blocker)
+}
+
+func (s *ManagerTestSuite) TestShutdownTimeout() {
+	g, err := s.manager.Create("manager_shutdown", s.objects)
+	s.Require().NoError(err)
+
+	started := make(chan struct{})
+	blocker := make(chan struct{})
+	defer close(blocker)
+	command := mock.CommandMock{}
+	command.On("Execute").Return(func() error {
+		close(started)
+		<-blocker
+		return nil
+	})
+	g.Queue().Put(&command)
+	<-started
+	s.fill(g)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+	defer cancel()
+	err = s.manager.Shutdown(ctx)
+	s.Require().ErrorIs(err, context.DeadlineExceeded)
+
+	_, err = s.manager.Create("manager_after_shutdown", s.objects)
+	s.Require().ErrorIs(err, ErrManagerClosed)
+	s.Require().ErrorIs(s.manager.Submit(interpreter.Message{GameID: "manager_shutdown"}), interpreter.ErrUnknownGame)
+}
+
+// fill puts no-op commands into the game queue until it is full.
+func (s *ManagerTestSuite) fill(g *Game) {
+	queue := g.Queue().(interface{ TryPut(core.Command) bool })
+	command := mock.CommandMock{}
+	command.On("Execute").Return(nil)
+	for queue.TryPut(&command) {
+	}
+}
+
+func (s *ManagerTestSuite) TestStopFullQueue() {
+	g, err := s.manager.Create("manager_stop_full", s.objects)
+	s.Require().NoError(err)
+
+	started := make(chan struct{})
+	blocker := make(chan struct{})
+	command := mock.CommandMock{}
+	command.On("Execute").Return(func() error {
+		close(started)
+		<-blocker
+		return nil
+	})
+	g.Queue().Put(&command)
+	<-started
+	s.fill(g)
+
+	s.Require().NoError(s.manager.Stop("manager_stop_full", false))
+	_, err = s.manager.Create("manager_stop_other", object.NewRegistry())
+	s.Require().NoError(err)
+
+	close(blocker)
+	<-g.Finished()
+}
+
+func (s *ManagerTestSuite) TestErrorHandler() {
+	handled := make(chan error, 1)
+	err := ioc.Resolve("IoC.Register", "ErrorHandlers.Default", func(params ...interface{}) interface{} {
+		return core.ErrorHandler(func(command core.Command, err error) {
+			handled <- err
+		})
+	}).(core.Command).Execute()
+	s.Require().NoError(err)
+	defer func() {
+		err := ioc.Resolve("IoC.Unregister", "ErrorHandlers.Default").(core.Command).Execute()
+		s.Require().NoError(err)
+	}()
+
+	_, err = s.manager.Create("manager_error_handler", s.objects)
+	s.Require().NoError(err)
+	s.Require().NoError(s.ship.SetProperty(object.Velocity, "fast"))
+
+	err = s.manager.Submit(interpreter.Message{
+		GameID:      "manager_error_handler",
+		ObjectID:    "548",
+		OperationID: "move",
+	})
+	s.Require().NoError(err)
+	s.Require().ErrorIs(<-handled, object.ErrInvalidProperty)
+}
+
+func (s *ManagerTestSuite) TestExecute() {
+	_, err := s.manager.Create("manager_execute", s.objects)
+	s.Require().NoError(err)
+
+	message := interpreter.Message{
+		GameID:      "manager_execute",
+		ObjectID:    "548",
+		OperationID: "move",
+	}
+	err = s.manager.Execute(context.Background(), message)
+	s.Require().NoError(err)
+
+	s.Require().NoError(s.ship.SetProperty(object.Velocity, "fast"))
+	err = s.manager.Execute(context.Background(), message)
+	s.Require().ErrorIs(err, object.ErrInvalidProperty)
+}
+
+func (s *ManagerTestSuite) TestOrderWrapper() {
+	_, err := s.manager.Create("manager_wrapper", s.objects)
+	s.Require().NoError(err)
+
+	errForbidden := fmt.Errorf("forbidden")
+	forbidden := mock.CommandMock{}
+	forbidden.On("Execute").Return(errForbidden)
+
+	var wrapped []interpreter.Message
+	s.manager.SetOrderWrapper(func(message interpreter.Message, command core.Command) core.Command {
+		wrapped = append(wrapped, message)
+		if message.Token != "secret" {
+			return &forbidden
+		}
+		return command
+	})
+
+	message := interpreter.Message{
+		GameID:      "manager_wrapper",
+		ObjectID:    "548",
+		OperationID: "move",
+	}
+	s.Require().ErrorIs(s.manager.Execute(context.Background(), message), errForbidden)
+
+	message.Token = "secret"
+	s.Require().NoError(s.manager.Execute(context.Background(), message))
+	s.Require().Len(wrapped, 2)
+}
+
+func (s *ManagerTestSuite) TestStartMove() {
+	fake := clock.NewFake(time.Unix(0, 0))
+	err := ioc.Resolve("IoC.Register", "Clock", func(params ...interface{}) interface{} {
+		return fake
+	}).(core.Command).Execute()
+	s.Require().NoError(err)
+	defer func() {
+		err := ioc.Resolve("IoC.Unregister", "Clock").(core.Command).Execute()
+		s.Require().NoError(err)
+	}()
+
+	g, err := s.manager.Create("manager_start_move", s.objects)
+	s.Require().NoError(err)
+
+	message := interpreter.Message{
+		GameID:      "manager_start_move",
+		ObjectID:    "548",
+		OperationID: "start_move",
+	}
+	s.Require().NoError(s.manager.Execute(context.Background(), message))
+	s.Require().Equal(1, g.Scheduler().Len())
+
+	fake.BlockUntil(1)
+	s.Require().Eventually(func() bool {
+		fake.Advance(scheduler.DefaultInterval)
+		position, err := s.ship.GetProperty(object.Position)
+		s.Require().NoError(err)
+		return position.(vector.Vector)[0] <= 12-7*3
+	}, time.Second, time.Millisecond)
+
+	message.OperationID = "stop_move"
+	s.Require().NoError(s.manager.Execute(context.Background(), message))
+	s.Require().Zero(g.Scheduler().Len())
+	s.Require().ErrorIs(s.manager.Execute(context.Background(), message), scheduler.ErrNotMoving)
+
+	position, err := s.ship.GetProperty(object.Position)
+	s.Require().NoError(err)
+	fake.Advance(scheduler.DefaultInterval)
+	s.Require().ErrorIs(s.manager.Execute(context.Background(), message), scheduler.ErrNotMoving)
+	s.Require().Equal(position, s.ship.Properties()[object.Position])
+}
+
+func (s *ManagerTestSuite) TestBeginMove() {
+	_, err := s.manager.Create("manager_begin_move", s.objects)
+	s.Require().NoError(err)
+
+	message := interpreter.Message{
+		GameID:      "manager_begin_move",
+		ObjectID:    "548",
+		OperationID: "begin_move",
+	}
+	s.Require().NoError(s.manager.Execute(context.Background(), message))
+
+	s.Require().Eventually(func() bool {
+		position, err := s.ship.GetProperty(object.Position)
+		s.Require().NoError(err)
+		return position.(vector.Vector)[0] <= 12-7*3
+	}, time.Second, time.Millisecond)
+
+	message.OperationID = "end_move"
+	s.Require().NoError(s.manager.Execute(context.Background(), message))
+	position, err := s.ship.GetProperty(object.Position)
+	s.Require().NoError(err)
+
+	s.Require().ErrorIs(s.manager.Execute(context.Background(), message), command.ErrNotRepeating)
+	s.Require().Equal(position, s.ship.Properties()[object.Position])
+}
+
+func (s *ManagerTestSuite) TestFire() {
+	for key, value := range map[string]interface{}{
+		object.Direction:        0,
+		object.DirectionsNumber: 8,
+		object.Ammo:             1,
+		object.ProjectileSpeed:  10,
+	} {
+		s.Require().NoError(s.ship.SetProperty(key, value))
+	}
+	g, err := s.manager.Create("manager_fire", s.objects)
+	s.Require().NoError(err)
+
+	message := interpreter.Message{
+		GameID:      "manager_fire",
+		ObjectID:    "548",
+		OperationID: "fire",
+	}
+	s.Require().NoError(s.manager.Execute(context.Background(), message))
+	s.Require().ErrorIs(s.manager.Execute(context.Background(), message), command.ErrNoAmmo)
+
+	projectile, err := s.objects.Get("projectile-1")
+	s.Require().NoError(err)
+	s.Require().Equal(object.KindProjectile, projectile.Properties()[object.Kind])
+	s.Require().Equal("548", projectile.Properties()[object.Shooter])
+	s.Require().Equal(1, g.Scheduler().Len())
+	s.Require().Eventually(func() bool {
+		position, err := projectile.GetProperty(object.Position)
+		s.Require().NoError(err)
+		return position.(vector.Vector)[0] >= 12+10*3
+	}, time.Second, time.Millisecond)
+
+	message.ObjectID = "projectile-1"
+	message.OperationID = "stop_move"
+	s.Require().NoError(s.manager.Execute(context.Background(), message))
+	s.Require().Zero(g.Scheduler().Len())
+}
+
+func (s *ManagerTestSuite) TestLargeRadius() {
+	s.Require().NoError(s.ship.SetProperty(object.Radius, collision.DefaultCellSize))
+	g, err := s.manager.Create("manager_large_radius", s.objects)
+	s.Require().NoError(err)
+	s.Require().Equal(collision.DefaultCellSize, g.Collisions().MaxRadius())
+}
+
+func (s *ManagerTestSuite) TestWorldMap() {
+	worldMap, err := world.NewMap(vector.New([]int{0, 0}), vector.New([]int{10, 10}), world.Destroy)
+	s.Require().NoError(err)
+	err = ioc.Resolve("IoC.Register", "Maps", func(params ...interface{}) interface{} {
+		if params[0] == "manager_map" {
+			return worldMap
+		}
+		return nil
+	}).(core.Command).Execute()
+	s.Require().NoError(err)
+	defer func() {
+		err := ioc.Resolve("IoC.Unregister", "Maps").(core.Command).Execute()
+		s.Require().NoError(err)
+	}()
+
+	_, err = s.manager.Create("manager_map", s.objects)
+	s.Require().NoError(err)
+
+	message := interpreter.Message{
+		GameID:      "manager_map",
+		ObjectID:    "548",
+		OperationID: "move",
+	}
+	s.Require().NoError(s.manager.Execute(context.Background(), message))
+	s.Require().NoError(s.manager.Execute(context.Background(), message))
+	s.Require().ErrorIs(s.manager.Execute(context.Background(), message), object.ErrUnknownObject)
+	s.Require().Empty(s.objects.IDs())
+}
+
+func (s *ManagerTestSuite) TestThrust() {
+	for key, value := range map[string]interface{}{
+		object.Direction:         0,
+		object.DirectionsNumber:  8,
+		object.Thrust:            4,
+		object.MaxSpeed:          10,
+		object.ThrustConsumption: 1,
+		object.Fuel:              5,
+		object.Drag:              50,
+	} {
+		s.Require().NoError(s.ship.SetProperty(key, value))
+	}
+	_, err := s.manager.Create("manager_thrust", s.objects)
+	s.Require().NoError(err)
+
+	message := interpreter.Message{
+		GameID:      "manager_thrust",
+		ObjectID:    "548",
+		OperationID: "thrust",
+	}
+	s.Require().NoError(s.manager.Execute(context.Background(), message))
+	s.Require().ErrorIs(s.manager.Execute(context.Background(), message), command.ErrNotEnoughFuel)
+	s.Require().Equal(vector.New([]int{-3, 3}), s.ship.Properties()[object.Velocity])
+
+	message.OperationID = "move"
+	s.Require().NoError(s.manager.Execute(context.Background(), message))
+	s.Require().Equal(vector.New([]int{9, 8}), s.ship.Properties()[object.Position])
+	s.Require().Equal(vector.New([]int{-1, 1}), s.ship.Properties()[object.Velocity])
+}
+
+type journalBuffer struct {
+	bytes.Buffer
+	closed chan struct{}
+}
+
+func (b *journalBuffer) Close() error {
+	close(b.closed)
+	return nil
+}
+
+func (s *ManagerTestSuite) TestJournal() {
+	buffer := &journalBuffer{closed: make(chan struct{})}
+	err := ioc.Resolve("IoC.Register", "Journals", func(params ...interface{}) interface{} {
+		if params[0] == "manager_journal" {
+			return buffer
+		}
+		return nil
+	}).(core.Command).Execute()
+	s.Require().NoError(err)
+	defer func() {
+		err := ioc.Resolve("IoC.Unregister", "Journals").(core.Command).Execute()
+		s.Require().NoError(err)
+	}()
+
+	fake := clock.NewFake(time.Unix(0, 0))
+	err = ioc.Resolve("IoC.Register", "Clock", func(params ...interface{}) interface{} {
+		return fake
+	}).(core.Command).Execute()
+	s.Require().NoError(err)
+	defer func() {
+		err := ioc.Resolve("IoC.Unregister", "Clock").(core.Command).Execute()
+		s.Require().NoError(err)
+	}()
+
+	for key, value := range map[string]interface{}{
+		object.Owner:            "alice",
+		object.Direction:        0,
+		object.DirectionsNumber: 8,
+		object.Ammo:             1,
+		object.ProjectileSpeed:  10,
+	} {
+		s.Require().NoError(s.ship.SetProperty(key, value))
+	}
+	target := object.New(map[string]interface{}{
+		object.Kind:     object.KindShip,
+		object.Position: vector.New([]int{45, 8}),
+		object.Radius:   5,
+		object.Health:   1,
+	})
+	s.Require().NoError(s.objects.Add("549", target))
+
+	g, err := s.manager.Create("manager_journal", s.objects)
+	s.Require().NoError(err)
+
+	message := interpreter.Message{
+		GameID:      "manager_journal",
+		ObjectID:    "548",
+		OperationID: "move",
+	}
+	s.Require().NoError(s.manager.Execute(context.Background(), message))
+	message.OperationID = "start_move"
+	message.Args = map[string]interface{}{object.Velocity: []interface{}{1.0, 2.0}}
+	s.Require().NoError(s.manager.Execute(context.Background(), message))
+	message.OperationID = "fire"
+	message.Args = nil
+	s.Require().NoError(s.manager.Execute(context.Background(), message))
+
+	fake.BlockUntil(1)
+	s.Require().Eventually(func() bool {
+		fake.Advance(scheduler.DefaultInterval)
+		return g.Scheduler().Len() == 1
+	}, time.Second, time.Millisecond)
+	_, err = s.objects.Get("549")
+	s.Require().ErrorIs(err, object.ErrUnknownObject)
+
+	message.OperationID = "stop_move"
+	s.Require().NoError(s.manager.Execute(context.Background(), message))
+	message.OperationID = "move_with_fuel"
+	s.Require().ErrorIs(s.manager.Execute(context.Background(), message), object.ErrNoProperty)
+	s.Require().NoError(s.manager.Stop("manager_journal", false))
+	<-buffer.closed
+
+	result, err := journal.Replay(&buffer.Buffer, NewReplayCodec)
+	s.Require().NoError(err)
+	s.Require().Equal(1, result.Checkpoints)
+	s.Require().Equal(s.objects.IDs(), result.Objects.IDs())
+
+	ship, err := result.Objects.Get("548")
+	s.Require().NoError(err)
+	s.Require().Equal(s.ship.Properties()[object.Position], ship.Properties()[object.Position])
+}
+
+func (s *ManagerTestSuite) TestSnapshot() {
+	path := filepath.Join(s.T().TempDir(), "manager_snapshot.json")
+	err := ioc.Resolve("IoC.Register", "Snapshots", func(params ...interface{}) interface{} {
+		if params[0] == "manager_snapshot" {
+			return path
+		}
+		return nil
+	}).(core.Command).Execute()
+	s.Require().NoError(err)
+	defer func() {
+		err := ioc.Resolve("IoC.Unregister", "Snapshots").(core.Command).Execute()
+		s.Require().NoError(err)
+	}()
+
+	s.objects.AddPlayer("alice")
+	game, err := s.manager.Create("manager_snapshot", s.objects)
+	s.Require().NoError(err)
+
+	message := interpreter.Message{
+		GameID:      "manager_snapshot",
+		ObjectID:    "548",
+		OperationID: "move",
+	}
+	s.Require().NoError(s.manager.Execute(context.Background(), message))
+	s.Require().NoError(s.manager.Stop("manager_snapshot", false))
+	<-game.Finished()
+
+	taken, err := snapshot.Load(path)
+	s.Require().NoError(err)
+	s.Require().Equal("manager_snapshot", taken.GameID)
+
+	restored, err := s.manager.Restore(taken)
+	s.Require().NoError(err)
+	s.Require().Equal([]string{"alice"}, restored.Objects().Players())
+	ship, err := restored.Objects().Get("548")
+	s.Require().NoError(err)
+	s.Require().Equal(s.ship.Properties(), ship.Properties())
+
+	s.Require().NoError(s.manager.Execute(context.Background(), message))
+	s.Require().Equal(vector.New([]int{-2, 11}), ship.Properties()[object.Position])
+}
+
+func (s *ManagerTestSuite) TestRestoreMovements() {
+	path := filepath.Join(s.T().TempDir(), "manager_movements.json")
+	err := ioc.Resolve("IoC.Register", "Snapshots", func(params ...interface{}) interface{} {
+		if params[0] == "manager_movements" {
+			return path
+		}
+		return nil
+	}).(core.Command).Execute()
+	s.Require().NoError(err)
+	defer func() {
+		err := ioc.Resolve("IoC.Unregister", "Snapshots").(core.Command).Execute()
+		s.Require().NoError(err)
+	}()
+
+	game, err := s.manager.Create("manager_movements", s.objects)
+	s.Require().NoError(err)
+
+	message := interpreter.Message{
+		GameID:      "manager_movements",
+		ObjectID:    "548",
+		OperationID: "start_move",
+	}
+	s.Require().NoError(s.manager.Execute(context.Background(), message))
+	s.Require().Equal(1, game.Scheduler().Len())
+	s.Require().NoError(s.manager.Stop("manager_movements", true))
+	<-game.Finished()
+
+	taken, err := snapshot.Load(path)
+	s.Require().NoError(err)
+	restored, err := s.manager.Restore(taken)
+	s.Require().NoError(err)
+	s.Require().Zero(restored.Scheduler().Len())
+	ship, err := restored.Objects().Get("548")
+	s.Require().NoError(err)
+	s.Require().Equal(vector.New([]int{-7, 3}), ship.Properties()[object.Velocity])
+
+	s.Require().NoError(s.manager.Execute(context.Background(), message))
+	s.Require().Equal(1, restored.Scheduler().Len())
+}
+
+func (s *ManagerTestSuite) TestRepository() {
+	games := repository.NewMemory()
+	err := ioc.Resolve("IoC.Register", "Repository", func(params ...interface{}) interface{} {
+		return games
+	}).(core.Command).Execute()
+	s.Require().NoError(err)
+	defer func() {
+		err := ioc.Resolve("IoC.Unregister", "Repository").(core.Command).Execute()
+		s.Require().NoError(err)
+	}()
+
+	s.objects.AddPlayer("alice")
+	game, err := s.manager.Create("manager_repository", s.objects)
+	s.Require().NoError(err)
+
+	message := interpreter.Message{
+		GameID:      "manager_repository",
+		ObjectID:    "548",
+		OperationID: "move",
+	}
+	s.Require().NoError(s.manager.Execute(context.Background(), message))
+	s.Require().NoError(s.manager.Stop("manager_repository", false))
+	<-game.Finished()
+
+	game, err = s.manager.Load(games, "manager_repository")
+	s.Require().NoError(err)
+	s.Require().Equal([]string{"alice"}, game.Objects().Players())
+	ship, err := game.Objects().Get("548")
+	s.Require().NoError(err)
+	s.Require().Equal(s.ship.Properties(), ship.Properties())
+
+	_, err = s.manager.Load(games, "manager_unknown")
+	s.Require().ErrorIs(err, repository.ErrUnknownGame)
+}
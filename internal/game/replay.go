@@ -0,0 +1,43 @@
+package game
+
+import (
+	"modules/internal/clock"
+	"modules/internal/collision"
+	"modules/internal/command"
+	"modules/internal/core"
+	"modules/internal/object"
+	"modules/internal/scheduler"
+)
+
+// NewReplayCodec creates the codec for journal.Replay to decode the commands
+// of a game journal into commands working on the replayed objects like the
+// ones of the game did. Nothing happens on its own in a replay though: the
+// scheduler moves the objects on the journaled ticks only, and neither the
+// collisions found nor the commands put into the queue are executed, since
+// the journal has the commands they led to.
+func NewReplayCodec(objects *object.Registry) (*command.Codec, error) {
+	g := &Game{
+		objects: objects,
+		events:  newEvents(),
+		tokens:  command.NewTokens(),
+		removed: map[string]*object.Object{},
+	}
+	g.scheduler = scheduler.NewScheduler(discardQueue{}, clock.NewReal(), scheduler.DefaultInterval)
+
+	err := g.createDetector()
+	if err != nil {
+		return nil, err
+	}
+	g.collisions.SetHandler(func(collision.Collision) {})
+
+	return g.newCodec(discardQueue{}), nil
+}
+
+// discardQueue drops the commands put into it.
+type discardQueue struct{}
+
+func (discardQueue) Put(core.Command) {}
+
+func (discardQueue) TryPut(core.Command) bool {
+	return true
+}
@@ -0,0 +1,352 @@
+package grpcapi
+
+//go:generate protoc -I ../../api --go_out=pb --go_opt=paths=source_relative --go-grpc_out=pb --go-grpc_opt=paths=source_relative game.proto
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"modules/internal/auth"
+	"modules/internal/command"
+	"modules/internal/game"
+	"modules/internal/grpcapi/pb"
+	"modules/internal/interpreter"
+	"modules/internal/vector"
+)
+
+const sendBufferLength = 256
+
+type Server struct {
+	pb.UnimplementedGameServiceServer
+
+	manager *game.Manager
+	auth    *auth.Service
+	streams atomic.Int64
+}
+
+func NewServer(manager *game.Manager) *Server {
+	return &Server{
+		manager: manager,
+	}
+}
+
+// SetAuthService makes joining a game require a token of one of its
+// participants, passed in the "authorization" metadata of the stream.
+func (s *Server) SetAuthService(service *auth.Service) {
+	s.auth = service
+}
+
+func (s *Server) Play(stream pb.GameService_PlayServer) error {
+	ctx, cancel := context.WithCancel(stream.Context())
+	p := &player{
+		ctx:     ctx,
+		stream:  stream,
+		manager: s.manager,
+		auth:    s.auth,
+		out:     make(chan *pb.ServerMessage, sendBufferLength),
+		joined:  map[string]*membership{},
+		token:   bearerToken(stream.Context()),
+		origin:  "grpc:" + strconv.FormatInt(s.streams.Add(1), 10),
+	}
+	defer p.leaveAll()
+	defer cancel()
+
+	sendErr := make(chan error, 1)
+	go func() {
+		sendErr <- p.send()
+	}()
+
+	recvErr := make(chan error, 1)
+	go func() {
+		recvErr <- p.receive()
+	}()
+
+	for {
+		select {
+		case err := <-sendErr:
+			return err
+		case err := <-recvErr:
+			if err != nil {
+				return err
+			}
+			// the client has stopped sending orders but still gets updates
+			recvErr = nil
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+type player struct {
+	ctx     context.Context
+	stream  pb.GameService_PlayServer
+	manager *game.Manager
+	auth    *auth.Service
+	out     chan *pb.ServerMessage
+	token   string
+	origin  string
+
+	mutex  sync.Mutex
+	joined map[string]*membership
+	wg     sync.WaitGroup
+}
+
+type membership struct {
+	cancel func()
+}
+
+func (p *player) send() error {
+	for {
+		select {
+		case message := <-p.out:
+			err := p.stream.Send(message)
+			if err != nil {
+				return err
+			}
+		case <-p.ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (p *player) receive() error {
+	for {
+		message, err := p.stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch payload := message.Payload.(type) {
+		case *pb.ClientMessage_Join:
+			err = p.join(payload.Join.GameId)
+			if err != nil {
+				p.reply(commandError("", payload.Join.GameId, "", "", "", err))
+			}
+		case *pb.ClientMessage_Order:
+			p.order(payload.Order)
+		default:
+			return status.Error(codes.InvalidArgument, "empty client message")
+		}
+	}
+}
+
+func (p *player) join(gameID string) error {
+	g, err := p.manager.Get(gameID)
+	if err != nil {
+		return err
+	}
+
+	if p.auth != nil {
+		_, err = p.auth.Authorize(interpreter.Message{GameID: gameID, Token: p.token})
+		if err != nil {
+			return err
+		}
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if _, ok := p.joined[gameID]; ok {
+		return nil
+	}
+
+	events, cancel := g.Subscribe()
+	m := &membership{cancel: cancel}
+	p.joined[gameID] = m
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		defer p.lagged(gameID, m)
+		for event := range events {
+			if failed, ok := event.(game.CommandFailed); ok && !p.sent(failed) {
+				continue
+			}
+
+			message, err := serverMessage(gameID, event)
+			if err != nil {
+				log.Printf("game %q events: %s", gameID, err)
+			}
+			if message != nil {
+				p.reply(message)
+			}
+		}
+	}()
+
+	return nil
+}
+
+// sent tells whether the failed command is an order of the player, other
+// players must not see its errors.
+func (p *player) sent(failed game.CommandFailed) bool {
+	order, ok := failed.Command.(*interpreter.OrderCommand)
+	return ok && order.Message().Origin == p.origin
+}
+
+func (p *player) order(order *pb.Order) {
+	message := interpreter.Message{
+		RequestID:   order.RequestId,
+		GameID:      order.GameId,
+		ObjectID:    order.ObjectId,
+		OperationID: order.OperationId,
+		Args:        order.Args.AsMap(),
+		Token:       p.token,
+		Origin:      p.origin,
+	}
+
+	err := p.manager.Submit(message)
+	if err != nil {
+		p.reply(commandError(order.RequestId, order.GameId, order.ObjectId, order.OperationId, "", err))
+	}
+}
+
+func (p *player) reply(message *pb.ServerMessage) {
+	select {
+	case p.out <- message:
+	case <-p.ctx.Done():
+	}
+}
+
+// lagged tells the player to join again if the game has closed the events
+// of a membership the player has not left.
+func (p *player) lagged(gameID string, m *membership) {
+	p.mutex.Lock()
+	current, ok := p.joined[gameID]
+	if ok && current == m {
+		delete(p.joined, gameID)
+	}
+	p.mutex.Unlock()
+
+	if ok && current == m {
+		p.reply(commandError("", gameID, "", "", "", game.ErrSubscriberLagging))
+	}
+}
+
+func (p *player) leaveAll() {
+	p.mutex.Lock()
+	for gameID, m := range p.joined {
+		m.cancel()
+		delete(p.joined, gameID)
+	}
+	p.mutex.Unlock()
+
+	p.wg.Wait()
+}
+
+// bearerToken returns the token passed by the client in the "authorization"
+// metadata of the stream.
+func bearerToken(ctx context.Context) string {
+	for _, value := range metadata.ValueFromIncomingContext(ctx, "authorization") {
+		if token, ok := strings.CutPrefix(value, "Bearer "); ok {
+			return strings.TrimSpace(token)
+		}
+	}
+
+	return ""
+}
+
+// serverMessage converts the event for the player. A state is sent even when
+// some of its properties cannot be converted, those are left out and
+// reported in the error.
+func serverMessage(gameID string, event game.Event) (*pb.ServerMessage, error) {
+	switch event := event.(type) {
+	case game.StateChanged:
+		properties, err := toStruct(event.Properties)
+
+		return &pb.ServerMessage{
+			Payload: &pb.ServerMessage_State{
+				State: &pb.ObjectState{
+					GameId:     gameID,
+					ObjectId:   event.ObjectID,
+					Properties: properties,
+					Removed:    event.Removed,
+				},
+			},
+		}, err
+	case game.CommandFailed:
+		var message interpreter.Message
+		if order, ok := event.Command.(*interpreter.OrderCommand); ok {
+			message = order.Message()
+		}
+
+		return commandError(message.RequestID, gameID, message.ObjectID, message.OperationID, command.TypeName(event.Command), event.Err), nil
+	default:
+		return nil, status.Errorf(codes.Internal, "unknown event %T", event)
+	}
+}
+
+func commandError(requestID, gameID, objectID, operationID, command string, err error) *pb.ServerMessage {
+	return &pb.ServerMessage{
+		Payload: &pb.ServerMessage_Error{
+			Error: &pb.CommandError{
+				RequestId:   requestID,
+				GameId:      gameID,
+				ObjectId:    objectID,
+				OperationId: operationID,
+				Command:     command,
+				Message:     err.Error(),
+			},
+		},
+	}
+}
+
+func toStruct(properties map[string]interface{}) (*structpb.Struct, error) {
+	result := &structpb.Struct{Fields: make(map[string]*structpb.Value, len(properties))}
+	var errs []error
+	for key, value := range properties {
+		converted, err := structpb.NewValue(toStructValue(value))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("property %q: %w", key, err))
+			continue
+		}
+		result.Fields[key] = converted
+	}
+
+	return result, errors.Join(errs...)
+}
+
+func toStructValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case vector.Vector:
+		result := make([]interface{}, len(value))
+		for i, item := range value {
+			result[i] = item
+		}
+		return result
+	case []string:
+		result := make([]interface{}, len(value))
+		for i, item := range value {
+			result[i] = item
+		}
+		return result
+	case map[string][]string:
+		result := make(map[string]interface{}, len(value))
+		for key, item := range value {
+			result[key] = toStructValue(item)
+		}
+		return result
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(value))
+		for key, item := range value {
+			result[key] = toStructValue(item)
+		}
+		return result
+	default:
+		return value
+	}
+}
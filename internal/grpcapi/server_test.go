@@ -0,0 +1,258 @@
+package grpcapi
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"modules/internal/auth"
+	"modules/internal/command"
+	"modules/internal/game"
+	"modules/internal/grpcapi/pb"
+	"modules/internal/interpreter"
+	"modules/internal/object"
+	"modules/internal/vector"
+)
+
+func TestServer(t *testing.T) {
+	suite.Run(t, new(ServerTestSuite))
+}
+
+type ServerTestSuite struct {
+	suite.Suite
+
+	manager  *game.Manager
+	object   *object.Object
+	service  *Server
+	server   *grpc.Server
+	conn     *grpc.ClientConn
+	client   pb.GameServiceClient
+	stream   pb.GameService_PlayClient
+	cancel   func()
+	listener *bufconn.Listener
+}
+
+func (s *ServerTestSuite) SetupSuite() {
+	s.Require().NoError(interpreter.RegisterOperations())
+}
+
+func (s *ServerTestSuite) SetupTest() {
+	s.manager = game.NewManager(10)
+	objects := object.NewRegistry()
+	s.object = object.New(map[string]interface{}{
+		object.Position: vector.New([]int{12, 5}),
+		object.Velocity: vector.New([]int{-7, 3}),
+	})
+	s.Require().NoError(objects.Add("548", s.object))
+	_, err := s.manager.Create("grpc_game", objects)
+	s.Require().NoError(err)
+
+	s.listener = bufconn.Listen(1 << 20)
+	s.server = grpc.NewServer()
+	s.service = NewServer(s.manager)
+	pb.RegisterGameServiceServer(s.server, s.service)
+	go func() {
+		_ = s.server.Serve(s.listener)
+	}()
+
+	s.conn, err = grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return s.listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	s.Require().NoError(err)
+	s.client = pb.NewGameServiceClient(s.conn)
+
+	var ctx context.Context
+	ctx, s.cancel = context.WithCancel(context.Background())
+	s.stream, err = s.client.Play(ctx)
+	s.Require().NoError(err)
+}
+
+func (s *ServerTestSuite) TearDownTest() {
+	s.cancel()
+	s.Require().NoError(s.conn.Close())
+	s.server.Stop()
+	s.Require().NoError(s.manager.Shutdown(context.Background()))
+}
+
+func (s *ServerTestSuite) send(message *pb.ClientMessage) {
+	s.Require().NoError(s.stream.Send(message))
+}
+
+func (s *ServerTestSuite) join() {
+	s.send(&pb.ClientMessage{Payload: &pb.ClientMessage_Join{Join: &pb.Join{GameId: "grpc_game"}}})
+
+	state := s.receive().GetState()
+	s.Require().NotNil(state)
+	s.Require().Equal("548", state.ObjectId)
+	s.Require().Equal(map[string]interface{}{
+		"position": []interface{}{float64(12), float64(5)},
+		"velocity": []interface{}{float64(-7), float64(3)},
+	}, state.Properties.AsMap())
+}
+
+func (s *ServerTestSuite) order(requestID, objectID, operationID string, args map[string]interface{}) {
+	structArgs, err := structpb.NewStruct(args)
+	s.Require().NoError(err)
+
+	s.send(&pb.ClientMessage{Payload: &pb.ClientMessage_Order{Order: &pb.Order{
+		RequestId:   requestID,
+		GameId:      "grpc_game",
+		ObjectId:    objectID,
+		OperationId: operationID,
+		Args:        structArgs,
+	}}})
+}
+
+func (s *ServerTestSuite) receive() *pb.ServerMessage {
+	message, err := s.stream.Recv()
+	s.Require().NoError(err)
+	return message
+}
+
+func (s *ServerTestSuite) TestStateDeltas() {
+	s.join()
+
+	s.order("1", "548", "move", nil)
+	state := s.receive().GetState()
+	s.Require().NotNil(state)
+	s.Require().Equal(map[string]interface{}{
+		"position": []interface{}{float64(5), float64(8)},
+	}, state.Properties.AsMap())
+
+	s.order("2", "548", "move", map[string]interface{}{"velocity": []interface{}{1, 1}})
+	state = s.receive().GetState()
+	s.Require().NotNil(state)
+	s.Require().Equal(map[string]interface{}{
+		"position": []interface{}{float64(6), float64(9)},
+		"velocity": []interface{}{float64(1), float64(1)},
+	}, state.Properties.AsMap())
+}
+
+func (s *ServerTestSuite) TestErrors() {
+	s.join()
+
+	s.order("1", "549", "move", nil)
+	commandError := s.receive().GetError()
+	s.Require().NotNil(commandError)
+	s.Require().Equal("1", commandError.RequestId)
+	s.Require().Equal("549", commandError.ObjectId)
+	s.Require().Contains(commandError.Message, object.ErrUnknownObject.Error())
+
+	s.order("2", "548", "move_with_fuel", nil)
+	commandError = s.receive().GetError()
+	s.Require().NotNil(commandError)
+	s.Require().Equal("2", commandError.RequestId)
+	s.Require().Equal("548", commandError.ObjectId)
+	s.Require().Equal("move_with_fuel", commandError.OperationId)
+	s.Require().Equal("OrderCommand", commandError.Command)
+	s.Require().Contains(commandError.Message, object.ErrNoProperty.Error())
+
+	s.send(&pb.ClientMessage{Payload: &pb.ClientMessage_Join{Join: &pb.Join{GameId: "no_such_game"}}})
+	commandError = s.receive().GetError()
+	s.Require().NotNil(commandError)
+	s.Require().Contains(commandError.Message, interpreter.ErrUnknownGame.Error())
+}
+
+func (s *ServerTestSuite) TestOtherPlayerErrors() {
+	s.join()
+	first := s.stream
+
+	var err error
+	s.stream, err = s.client.Play(context.Background())
+	s.Require().NoError(err)
+	s.join()
+	s.order("1", "548", "move_with_fuel", nil)
+	s.Require().NotNil(s.receive().GetError())
+
+	s.stream = first
+	s.order("2", "548", "move", nil)
+	s.Require().NotNil(s.receive().GetState())
+}
+
+func (s *ServerTestSuite) TestCloseSend() {
+	s.join()
+	s.order("1", "548", "move", nil)
+	s.Require().NoError(s.stream.CloseSend())
+
+	state := s.receive().GetState()
+	s.Require().NotNil(state)
+}
+
+func (s *ServerTestSuite) TestAuth() {
+	service, err := auth.LoadService("../auth/testdata/private.pem", "../auth/testdata/public.pem", time.Hour)
+	s.Require().NoError(err)
+	service.RegisterGame("grpc_game", []string{"alice", "bob"})
+	s.manager.SetOrderWrapper(service.Wrap)
+	s.service.SetAuthService(service)
+
+	s.send(&pb.ClientMessage{Payload: &pb.ClientMessage_Join{Join: &pb.Join{GameId: "grpc_game"}}})
+	commandError := s.receive().GetError()
+	s.Require().NotNil(commandError)
+	s.Require().Contains(commandError.Message, auth.ErrInvalidToken.Error())
+
+	s.order("1", "548", "move", nil)
+	commandError = s.receive().GetError()
+	s.Require().NotNil(commandError)
+	s.Require().Contains(commandError.Message, auth.ErrInvalidToken.Error())
+
+	service.RegisterGame("grpc_other_game", []string{"carol"})
+	token, err := service.Issue("grpc_other_game", "carol")
+	s.Require().NoError(err)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+	s.stream, err = s.client.Play(ctx)
+	s.Require().NoError(err)
+	s.send(&pb.ClientMessage{Payload: &pb.ClientMessage_Join{Join: &pb.Join{GameId: "grpc_game"}}})
+	commandError = s.receive().GetError()
+	s.Require().NotNil(commandError)
+	s.Require().Contains(commandError.Message, auth.ErrForbidden.Error())
+
+	token, err = service.Issue("grpc_game", "alice")
+	s.Require().NoError(err)
+	ctx = metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+	s.stream, err = s.client.Play(ctx)
+	s.Require().NoError(err)
+	s.join()
+
+	s.Require().NoError(s.object.SetProperty(object.Owner, "bob"))
+	s.order("2", "548", "move", nil)
+	commandError = s.receive().GetError()
+	s.Require().NotNil(commandError)
+	s.Require().Equal("2", commandError.RequestId)
+	s.Require().Equal("move", commandError.OperationId)
+	s.Require().Contains(commandError.Message, command.ErrPermissionDenied.Error())
+
+	s.Require().NoError(s.object.SetProperty(object.ACL, map[string][]string{"alice": {"move"}}))
+	s.order("3", "548", "move", nil)
+	state := s.receive().GetState()
+	s.Require().NotNil(state)
+	s.Require().Equal("548", state.ObjectId)
+	s.Require().Equal(map[string]interface{}{
+		"position": []interface{}{float64(5), float64(8)},
+		"owner":    "bob",
+		"acl":      map[string]interface{}{"alice": []interface{}{"move"}},
+	}, state.Properties.AsMap())
+}
+
+func (s *ServerTestSuite) TestUnsupportedProperty() {
+	message, err := serverMessage("grpc", game.StateChanged{
+		ObjectID:   "548",
+		Properties: map[string]interface{}{"position": vector.New([]int{5, 8}), "clock": time.Second},
+	})
+	s.Require().ErrorContains(err, `property "clock"`)
+	state := message.GetState()
+	s.Require().NotNil(state)
+	s.Require().Equal(map[string]interface{}{
+		"position": []interface{}{float64(5), float64(8)},
+	}, state.Properties.AsMap())
+}
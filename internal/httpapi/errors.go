@@ -0,0 +1,59 @@
+package httpapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"modules/internal/auth"
+	"modules/internal/command"
+	"modules/internal/game"
+	"modules/internal/interpreter"
+	"modules/internal/object"
+	"modules/internal/scheduler"
+)
+
+var (
+	ErrNotFound = fmt.Errorf("not found")
+
+	ErrMethodNotAllowed = fmt.Errorf("method not allowed")
+
+	ErrInvalidRequest = fmt.Errorf("invalid request")
+
+	ErrBodyTooLarge = fmt.Errorf("request body too large")
+
+	ErrUnauthorized = fmt.Errorf("admin key required")
+)
+
+var statuses = []struct {
+	err    error
+	status int
+}{
+	{ErrNotFound, http.StatusNotFound},
+	{ErrMethodNotAllowed, http.StatusMethodNotAllowed},
+	{ErrInvalidRequest, http.StatusBadRequest},
+	{ErrBodyTooLarge, http.StatusRequestEntityTooLarge},
+	{ErrUnauthorized, http.StatusUnauthorized},
+	{interpreter.ErrUnknownGame, http.StatusNotFound},
+	{object.ErrUnknownObject, http.StatusNotFound},
+	{interpreter.ErrUnknownOperation, http.StatusBadRequest},
+	{interpreter.ErrInvalidArg, http.StatusBadRequest},
+	{object.ErrDuplicateObject, http.StatusBadRequest},
+	{auth.ErrInvalidToken, http.StatusUnauthorized},
+	{auth.ErrUnknownParticipant, http.StatusForbidden},
+	{auth.ErrForbidden, http.StatusForbidden},
+	{game.ErrGameExists, http.StatusConflict},
+	{game.ErrInvalidGameID, http.StatusBadRequest},
+	{game.ErrManagerClosed, http.StatusServiceUnavailable},
+	{interpreter.ErrQueueFull, http.StatusServiceUnavailable},
+	{command.ErrPermissionDenied, http.StatusForbidden},
+	{command.ErrNotEnoughFuel, http.StatusConflict},
+	{scheduler.ErrNotMoving, http.StatusConflict},
+	{command.ErrNotRepeating, http.StatusConflict},
+	{command.ErrNoAmmo, http.StatusConflict},
+	{command.ErrCooldown, http.StatusConflict},
+	{command.ErrUnsupportedDimension, http.StatusUnprocessableEntity},
+	{object.ErrNoProperty, http.StatusUnprocessableEntity},
+	{object.ErrInvalidProperty, http.StatusUnprocessableEntity},
+	{context.DeadlineExceeded, http.StatusGatewayTimeout},
+}
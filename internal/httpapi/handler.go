@@ -0,0 +1,358 @@
+package httpapi
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"modules/internal/auth"
+	"modules/internal/game"
+	"modules/internal/interpreter"
+	"modules/internal/object"
+)
+
+const DefaultMaxBodySize = 1 << 20
+
+type Handler struct {
+	manager     *game.Manager
+	maxBodySize int64
+	waitTimeout time.Duration
+	auth        *auth.Service
+	adminKey    string
+}
+
+func NewHandler(manager *game.Manager, maxBodySize int64, waitTimeout time.Duration) *Handler {
+	return &Handler{
+		manager:     manager,
+		maxBodySize: maxBodySize,
+		waitTimeout: waitTimeout,
+	}
+}
+
+// SetAuthService makes the handler register the players of created games
+// and issue their tokens. Orders pass the token in the Authorization header.
+func (h *Handler) SetAuthService(service *auth.Service) {
+	h.auth = service
+}
+
+// SetAdminKey sets the key that creating games and issuing tokens require as
+// the bearer token once the auth service is set. Without a key both are
+// refused then, since anyone could get the token of any player otherwise.
+func (h *Handler) SetAdminKey(key string) {
+	h.adminKey = key
+}
+
+type createGameRequest struct {
+	ID      string                            `json:"id"`
+	Objects map[string]map[string]interface{} `json:"objects"`
+	Players map[string][]string               `json:"players"`
+}
+
+type gameResponse struct {
+	ID      string   `json:"id"`
+	Objects []string `json:"objects"`
+	// Tokens are the tokens of the players by id if auth is enabled.
+	Tokens map[string]string `json:"tokens,omitempty"`
+}
+
+type orderRequest struct {
+	ObjectID    string                 `json:"object_id"`
+	OperationID string                 `json:"operation_id"`
+	Args        map[string]interface{} `json:"args"`
+}
+
+type tokenRequest struct {
+	PlayerID string `json:"player_id"`
+}
+
+type tokenResponse struct {
+	Token string `json:"token"`
+}
+
+type objectResponse struct {
+	ID         string                 `json:"id"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// ServeHTTP serves
+//
+//	POST /games
+//	POST /games/{game_id}/orders[?wait=true]
+//	POST /games/{game_id}/tokens
+//	GET  /games/{game_id}/objects/{object_id}
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) == 0 || parts[0] != "games" {
+		writeError(w, fmt.Errorf("%w: %s", ErrNotFound, r.URL.Path))
+		return
+	}
+
+	switch {
+	case len(parts) == 1:
+		h.allow(w, r, http.MethodPost, h.createGame)
+	case len(parts) == 3 && parts[2] == "orders":
+		h.allow(w, r, http.MethodPost, func(w http.ResponseWriter, r *http.Request) {
+			h.submitOrder(w, r, parts[1])
+		})
+	case len(parts) == 3 && parts[2] == "tokens":
+		h.allow(w, r, http.MethodPost, func(w http.ResponseWriter, r *http.Request) {
+			h.issueToken(w, r, parts[1])
+		})
+	case len(parts) == 4 && parts[2] == "objects":
+		h.allow(w, r, http.MethodGet, func(w http.ResponseWriter, r *http.Request) {
+			h.getObject(w, r, parts[1], parts[3])
+		})
+	default:
+		writeError(w, fmt.Errorf("%w: %s", ErrNotFound, r.URL.Path))
+	}
+}
+
+func (h *Handler) allow(w http.ResponseWriter, r *http.Request, method string, handle http.HandlerFunc) {
+	if r.Method != method {
+		w.Header().Set("Allow", method)
+		writeError(w, fmt.Errorf("%w: %s", ErrMethodNotAllowed, r.Method))
+		return
+	}
+
+	handle(w, r)
+}
+
+// authorizeAdmin checks the admin key if auth is enabled.
+func (h *Handler) authorizeAdmin(r *http.Request) error {
+	if h.auth == nil {
+		return nil
+	}
+
+	key := bearerToken(r)
+	if h.adminKey == "" || subtle.ConstantTimeCompare([]byte(key), []byte(h.adminKey)) != 1 {
+		return ErrUnauthorized
+	}
+
+	return nil
+}
+
+func (h *Handler) createGame(w http.ResponseWriter, r *http.Request) {
+	err := h.authorizeAdmin(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	var request createGameRequest
+	err = h.decode(w, r, &request)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	err = game.ValidateID(request.ID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	objects := object.NewRegistry()
+	for id, properties := range request.Objects {
+		properties, err := object.ConvertProperties(properties)
+		if err != nil {
+			writeError(w, fmt.Errorf("%w: object %q: %s", ErrInvalidRequest, id, err))
+			return
+		}
+
+		err = objects.Add(id, object.New(properties))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+
+	players := make([]string, 0, len(request.Players))
+	for playerID, objectIDs := range request.Players {
+		err = objects.SetOwner(playerID, objectIDs...)
+		if err != nil {
+			writeError(w, fmt.Errorf("%w: player %q: %s", ErrInvalidRequest, playerID, err))
+			return
+		}
+		players = append(players, playerID)
+	}
+
+	g, err := h.manager.Create(request.ID, objects)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	response := gameResponse{
+		ID:      g.ID(),
+		Objects: g.Objects().IDs(),
+	}
+
+	if h.auth != nil {
+		h.auth.RegisterGame(request.ID, players)
+
+		response.Tokens = make(map[string]string, len(players))
+		for _, playerID := range players {
+			response.Tokens[playerID], err = h.auth.Issue(request.ID, playerID)
+			if err != nil {
+				writeError(w, errors.Join(err, h.manager.Stop(request.ID, true)))
+				return
+			}
+		}
+	}
+
+	writeJSON(w, http.StatusCreated, response)
+}
+
+func (h *Handler) submitOrder(w http.ResponseWriter, r *http.Request, gameID string) {
+	var request orderRequest
+	err := h.decode(w, r, &request)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	if request.ObjectID == "" || request.OperationID == "" {
+		writeError(w, fmt.Errorf("%w: object_id and operation_id are required", ErrInvalidRequest))
+		return
+	}
+
+	message := interpreter.Message{
+		GameID:      gameID,
+		ObjectID:    request.ObjectID,
+		OperationID: request.OperationID,
+		Args:        request.Args,
+		Token:       bearerToken(r),
+	}
+
+	if r.URL.Query().Get("wait") != "true" {
+		err = h.manager.Submit(message)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.waitTimeout)
+	defer cancel()
+
+	err = h.manager.Execute(ctx, message)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) issueToken(w http.ResponseWriter, r *http.Request, gameID string) {
+	if h.auth == nil {
+		writeError(w, fmt.Errorf("%w: %s", ErrNotFound, r.URL.Path))
+		return
+	}
+
+	err := h.authorizeAdmin(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	var request tokenRequest
+	err = h.decode(w, r, &request)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	token, err := h.auth.Issue(gameID, request.PlayerID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, tokenResponse{
+		Token: token,
+	})
+}
+
+func (h *Handler) getObject(w http.ResponseWriter, r *http.Request, gameID string, objectID string) {
+	g, err := h.manager.Get(gameID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	o, err := g.Objects().Get(objectID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, objectResponse{
+		ID:         objectID,
+		Properties: o.Properties(),
+	})
+}
+
+func (h *Handler) decode(w http.ResponseWriter, r *http.Request, value interface{}) error {
+	body := http.MaxBytesReader(w, r.Body, h.maxBodySize)
+	decoder := json.NewDecoder(body)
+	decoder.DisallowUnknownFields()
+
+	err := decoder.Decode(value)
+	var maxBytesError *http.MaxBytesError
+	if errors.As(err, &maxBytesError) {
+		return fmt.Errorf("%w: limit is %d bytes", ErrBodyTooLarge, maxBytesError.Limit)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidRequest, err)
+	}
+
+	if decoder.More() {
+		return fmt.Errorf("%w: unexpected data after JSON body", ErrInvalidRequest)
+	}
+
+	return nil
+}
+
+func bearerToken(r *http.Request) string {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return ""
+	}
+
+	return strings.TrimSpace(token)
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	writeJSON(w, statusOf(err), errorResponse{
+		Error: err.Error(),
+	})
+}
+
+func statusOf(err error) int {
+	for _, s := range statuses {
+		if errors.Is(err, s.err) {
+			return s.status
+		}
+	}
+
+	return http.StatusInternalServerError
+}
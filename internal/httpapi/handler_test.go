@@ -0,0 +1,226 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"modules/internal/auth"
+	"modules/internal/game"
+	"modules/internal/interpreter"
+)
+
+func TestHandler(t *testing.T) {
+	suite.Run(t, new(HandlerTestSuite))
+}
+
+type HandlerTestSuite struct {
+	suite.Suite
+
+	manager *game.Manager
+	server  *httptest.Server
+}
+
+func (s *HandlerTestSuite) SetupSuite() {
+	s.Require().NoError(interpreter.RegisterOperations())
+}
+
+func (s *HandlerTestSuite) SetupTest() {
+	s.manager = game.NewManager(10)
+	s.server = httptest.NewServer(NewHandler(s.manager, 512, time.Second))
+}
+
+func (s *HandlerTestSuite) TearDownTest() {
+	s.server.Close()
+	s.Require().NoError(s.manager.Shutdown(context.Background()))
+}
+
+func (s *HandlerTestSuite) do(method, path, body string) (int, map[string]interface{}) {
+	return s.doWithToken(method, path, body, "")
+}
+
+func (s *HandlerTestSuite) doWithToken(method, path, body, token string) (int, map[string]interface{}) {
+	request, err := http.NewRequest(method, s.server.URL+path, strings.NewReader(body))
+	s.Require().NoError(err)
+	if token != "" {
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	response, err := s.server.Client().Do(request)
+	s.Require().NoError(err)
+	defer response.Body.Close()
+
+	var result map[string]interface{}
+	if response.ContentLength != 0 {
+		s.Require().Equal("application/json", response.Header.Get("Content-Type"))
+		s.Require().NoError(json.NewDecoder(response.Body).Decode(&result))
+	}
+
+	return response.StatusCode, result
+}
+
+func (s *HandlerTestSuite) createGame(id string) {
+	status, body := s.do(http.MethodPost, "/games", `{
+		"id": "`+id+`",
+		"objects": {
+			"548": {"position": [12, 5], "velocity": [-7, 3], "fuel": 100, "consumption": 70},
+			"3d": {"velocity": [1, 2, 3], "direction": 0, "angular_velocity": 1, "directions_number": 8}
+		}
+	}`)
+	s.Require().Equal(http.StatusCreated, status, body)
+	s.Require().Equal(map[string]interface{}{"id": id, "objects": []interface{}{"3d", "548"}}, body)
+}
+
+func (s *HandlerTestSuite) TestOrder() {
+	s.createGame("http_order")
+
+	status, body := s.do(http.MethodPost, "/games/http_order/orders?wait=true", `{"object_id": "548", "operation_id": "move"}`)
+	s.Require().Equal(http.StatusNoContent, status, body)
+
+	status, _ = s.do(http.MethodPost, "/games/http_order/orders", `{"object_id": "548", "operation_id": "move", "args": {"velocity": [1, 1]}}`)
+	s.Require().Equal(http.StatusAccepted, status)
+
+	s.Require().Eventually(func() bool {
+		status, body = s.do(http.MethodGet, "/games/http_order/objects/548", "")
+		s.Require().Equal(http.StatusOK, status)
+		return body["properties"].(map[string]interface{})["position"].([]interface{})[0] == float64(6)
+	}, time.Second, time.Millisecond)
+
+	s.Require().Equal(map[string]interface{}{
+		"id": "548",
+		"properties": map[string]interface{}{
+			"position":    []interface{}{float64(6), float64(9)},
+			"velocity":    []interface{}{float64(1), float64(1)},
+			"fuel":        float64(100),
+			"consumption": float64(70),
+		},
+	}, body)
+}
+
+func (s *HandlerTestSuite) TestErrors() {
+	s.createGame("http_errors")
+
+	tests := []struct {
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{http.MethodPost, "/games", `{"id": "http_errors"}`, http.StatusConflict},
+		{http.MethodPost, "/games", `{}`, http.StatusBadRequest},
+		{http.MethodPost, "/games", `{"id": "../../x"}`, http.StatusBadRequest},
+		{http.MethodPost, "/games", `{"id": "x", "color": "red"}`, http.StatusBadRequest},
+		{http.MethodPost, "/games", `{"id": "` + strings.Repeat("x", 1024) + `"}`, http.StatusRequestEntityTooLarge},
+		{http.MethodGet, "/games", ``, http.StatusMethodNotAllowed},
+		{http.MethodGet, "/players", ``, http.StatusNotFound},
+		{http.MethodPost, "/games/http_errors/orders", `{"object_id": "548"}`, http.StatusBadRequest},
+		{http.MethodPost, "/games/http_errors/orders", `{"object_id": "548", "operation_id": "teleport"}`, http.StatusBadRequest},
+		{http.MethodPost, "/games/http_errors/orders", `{"object_id": "1", "operation_id": "move"}`, http.StatusNotFound},
+		{http.MethodPost, "/games/no_such_game/orders", `{"object_id": "548", "operation_id": "move"}`, http.StatusNotFound},
+		{http.MethodPost, "/games/http_errors/orders?wait=true", `{"object_id": "548", "operation_id": "rotate"}`, http.StatusUnprocessableEntity},
+		{http.MethodPost, "/games/http_errors/orders?wait=true", `{"object_id": "3d", "operation_id": "rotate"}`, http.StatusUnprocessableEntity},
+		{http.MethodPost, "/games/http_errors/orders?wait=true", `{"object_id": "548", "operation_id": "move_with_fuel"}`, http.StatusNoContent},
+		{http.MethodPost, "/games/http_errors/orders?wait=true", `{"object_id": "548", "operation_id": "move_with_fuel"}`, http.StatusConflict},
+		{http.MethodGet, "/games/http_errors/objects/1", ``, http.StatusNotFound},
+	}
+
+	for _, test := range tests {
+		status, body := s.do(test.method, test.path, test.body)
+		s.Require().Equal(test.status, status, "%s %s %s: %v", test.method, test.path, test.body, body)
+		if test.status != http.StatusNoContent {
+			s.Require().NotEmpty(body["error"])
+		}
+	}
+}
+
+func (s *HandlerTestSuite) TestAuth() {
+	service, err := auth.LoadService("../auth/testdata/private.pem", "../auth/testdata/public.pem", time.Hour)
+	s.Require().NoError(err)
+
+	handler := NewHandler(s.manager, 512, time.Second)
+	handler.SetAuthService(service)
+	s.manager.SetOrderWrapper(service.Wrap)
+	s.server.Config.Handler = handler
+
+	status, body := s.do(http.MethodPost, "/games", `{"id": "http_auth", "objects": {}}`)
+	s.Require().Equal(http.StatusUnauthorized, status, body)
+
+	handler.SetAdminKey("admin")
+	status, body = s.doWithToken(http.MethodPost, "/games", `{"id": "http_auth", "objects": {}}`, "not admin")
+	s.Require().Equal(http.StatusUnauthorized, status, body)
+
+	status, body = s.doWithToken(http.MethodPost, "/games", `{
+		"id": "http_auth",
+		"objects": {
+			"548": {"position": [12, 5], "velocity": [-7, 3]},
+			"549": {"position": [0, 0], "velocity": [1, 1]},
+			"550": {"position": [0, 0], "velocity": [1, 1], "acl": {"alice": ["move"]}}
+		},
+		"players": {"alice": ["548"], "bob": ["549", "550"]}
+	}`, "admin")
+	s.Require().Equal(http.StatusCreated, status, body)
+	tokens := body["tokens"].(map[string]interface{})
+	s.Require().Len(tokens, 2)
+	token := tokens["alice"].(string)
+
+	status, body = s.do(http.MethodPost, "/games/http_auth/tokens", `{"player_id": "alice"}`)
+	s.Require().Equal(http.StatusUnauthorized, status, body)
+
+	status, body = s.doWithToken(http.MethodPost, "/games/http_auth/tokens", `{"player_id": "alice"}`, token)
+	s.Require().Equal(http.StatusUnauthorized, status, body)
+
+	status, body = s.doWithToken(http.MethodPost, "/games/http_auth/tokens", `{"player_id": "alice"}`, "admin")
+	s.Require().Equal(http.StatusCreated, status, body)
+	s.Require().NotEmpty(body["token"])
+
+	status, body = s.doWithToken(http.MethodPost, "/games/http_auth/tokens", `{"player_id": "carol"}`, "admin")
+	s.Require().Equal(http.StatusForbidden, status, body)
+
+	order := `{"object_id": "548", "operation_id": "move"}`
+	status, body = s.do(http.MethodPost, "/games/http_auth/orders?wait=true", order)
+	s.Require().Equal(http.StatusUnauthorized, status, body)
+
+	status, body = s.doWithToken(http.MethodPost, "/games/http_auth/orders?wait=true", order, "token")
+	s.Require().Equal(http.StatusUnauthorized, status, body)
+
+	status, body = s.doWithToken(http.MethodPost, "/games/http_auth/orders?wait=true", `{"object_id": "549", "operation_id": "move"}`, token)
+	s.Require().Equal(http.StatusForbidden, status, body)
+
+	status, body = s.doWithToken(http.MethodPost, "/games/http_auth/orders", `{"object_id": "549", "operation_id": "move"}`, token)
+	s.Require().Equal(http.StatusForbidden, status, body)
+
+	status, body = s.doWithToken(http.MethodPost, "/games/http_auth/orders?wait=true", order, token)
+	s.Require().Equal(http.StatusNoContent, status, body)
+
+	status, body = s.doWithToken(http.MethodPost, "/games/http_auth/orders?wait=true", `{"object_id": "550", "operation_id": "move"}`, token)
+	s.Require().Equal(http.StatusNoContent, status, body)
+
+	status, body = s.doWithToken(http.MethodPost, "/games/http_auth/orders?wait=true", `{"object_id": "550", "operation_id": "rotate"}`, token)
+	s.Require().Equal(http.StatusForbidden, status, body)
+
+	status, body = s.do(http.MethodGet, "/games/http_auth/objects/550", "")
+	s.Require().Equal(http.StatusOK, status, body)
+	s.Require().Equal("bob", body["properties"].(map[string]interface{})["owner"])
+
+	status, body = s.doWithToken(http.MethodPost, "/games", `{"id": "http_auth_2", "objects": {}, "players": {"alice": ["548"]}}`, "admin")
+	s.Require().Equal(http.StatusBadRequest, status, body)
+}
+
+func (s *HandlerTestSuite) TestIssueFailure() {
+	handler := NewHandler(s.manager, 512, time.Second)
+	handler.SetAuthService(auth.NewService("not a key", nil, time.Hour))
+	handler.SetAdminKey("admin")
+	s.server.Config.Handler = handler
+
+	status, body := s.doWithToken(http.MethodPost, "/games", `{"id": "http_issue", "objects": {"548": {}}, "players": {"alice": ["548"]}}`, "admin")
+	s.Require().Equal(http.StatusInternalServerError, status, body)
+
+	_, err := s.manager.Get("http_issue")
+	s.Require().ErrorIs(err, interpreter.ErrUnknownGame)
+}
@@ -0,0 +1,61 @@
+package interpreter
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"modules/internal/command"
+	"modules/internal/core"
+	"modules/internal/object"
+	"modules/internal/scheduler"
+)
+
+// RegisterCodec makes the codec know the commands the operations create on
+// their own: the args of an order, encoded as JSON values, and shots, which
+// add the projectiles to the objects and move them on the scheduler.
+func RegisterCodec(codec *command.Codec, objects *object.Registry, s *scheduler.Scheduler) {
+	codec.Register("set_args", &setArgsCommand{}, func(c *command.Codec, cmd core.Command) (command.DTO, error) {
+		set := cmd.(*setArgsCommand)
+		id, err := c.ObjectID(set.object)
+		if err != nil {
+			return command.DTO{}, err
+		}
+
+		args := make(map[string]string, len(set.args))
+		for key, value := range set.args {
+			data, err := json.Marshal(value)
+			if err != nil {
+				return command.DTO{}, fmt.Errorf("%q: %w", key, err)
+			}
+			args[key] = string(data)
+		}
+
+		return command.DTO{ObjectID: id, Args: args}, nil
+	}, func(c *command.Codec, dto command.DTO) (core.Command, error) {
+		target, err := c.Object(dto)
+		if err != nil {
+			return nil, err
+		}
+
+		values := make(map[string]interface{}, len(dto.Args))
+		for key, data := range dto.Args {
+			var value interface{}
+			err := json.Unmarshal([]byte(data), &value)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %q: %w", command.ErrInvalidEncoding, key, err)
+			}
+			values[key] = value
+		}
+
+		args, err := convertArgs(values)
+		if err != nil {
+			return nil, err
+		}
+
+		return &setArgsCommand{object: target.Object(), args: args}, nil
+	})
+
+	command.RegisterShoot(codec, func(shooter *object.Adapter) command.ProjectileFactory {
+		return projectileFactory(objects, s, shooter)
+	})
+}
@@ -0,0 +1,15 @@
+package interpreter
+
+import "fmt"
+
+var (
+	ErrUnknownGame = fmt.Errorf("unknown game")
+
+	ErrUnknownOperation = fmt.Errorf("unknown operation")
+
+	ErrInvalidArg = fmt.Errorf("invalid argument")
+
+	ErrQueueFull = fmt.Errorf("game queue is full")
+
+	ErrMissingDependency = fmt.Errorf("game scope lacks a dependency")
+)
@@ -0,0 +1,196 @@
+package interpreter
+
+import (
+	"errors"
+	"fmt"
+
+	"modules/internal/command"
+	"modules/internal/core"
+	"modules/internal/ioc"
+	"modules/internal/object"
+	"modules/internal/vector"
+)
+
+type Message struct {
+	RequestID   string                 `json:"request_id,omitempty"`
+	GameID      string                 `json:"game_id"`
+	ObjectID    string                 `json:"object_id"`
+	OperationID string                 `json:"operation_id"`
+	Args        map[string]interface{} `json:"args"`
+	// PlayerID is set once the player sending the message is authorized.
+	PlayerID string `json:"player_id,omitempty"`
+	// Token authorizes the player sending the message.
+	Token string `json:"-"`
+	// Origin identifies the connection the message came from, so that the
+	// failure of the order is reported back to it only.
+	Origin string `json:"-"`
+}
+
+// InterpretCommand turns a player message into a command of the game and
+// puts it into the game queue. It resolves the operation in the game scope
+// and restores the ioc scope of the calling goroutine afterwards. It fails
+// with ErrQueueFull rather than wait for room in the queue of a busy game.
+type InterpretCommand struct {
+	message Message
+	report  func(err error)
+}
+
+func NewInterpretCommand(message Message) *InterpretCommand {
+	return &InterpretCommand{
+		message: message,
+	}
+}
+
+// NewInterpretCommandWithReport makes the queued command call report with
+// its outcome once the game has executed it.
+func NewInterpretCommandWithReport(message Message, report func(err error)) *InterpretCommand {
+	return &InterpretCommand{
+		message: message,
+		report:  report,
+	}
+}
+
+// SetPlayerID makes the order be executed on behalf of the player. The
+// operation is then checked against the owner and ACL of the object, and an
+// order the player may not give is not queued.
+func (c *InterpretCommand) SetPlayerID(playerID string) {
+	c.message.PlayerID = playerID
+}
+
+func (c *InterpretCommand) Execute() error {
+	restore := ioc.Resolve("Scopes.Inherit").(core.Command)
+	defer restore.Execute()
+
+	err := ioc.Resolve("Scopes.Current", c.message.GameID).(core.Command).Execute()
+	if errors.Is(err, ioc.ErrNoSuchScope) {
+		return fmt.Errorf("%w: %q", ErrUnknownGame, c.message.GameID)
+	}
+	if err != nil {
+		return err
+	}
+
+	objects, ok := ioc.Resolve("Game.Objects").(*object.Registry)
+	if !ok {
+		return fmt.Errorf("%w: %q has no objects", ErrUnknownGame, c.message.GameID)
+	}
+
+	queue, ok := ioc.Resolve("Game.Queue").(core.Queue)
+	if !ok {
+		return fmt.Errorf("%w: %q has no queue", ErrUnknownGame, c.message.GameID)
+	}
+
+	target, err := objects.Get(c.message.ObjectID)
+	if err != nil {
+		return err
+	}
+
+	args, err := convertArgs(c.message.Args)
+	if err != nil {
+		return err
+	}
+
+	var operation core.Command
+	switch resolved := ioc.Resolve("Operations."+c.message.OperationID, target).(type) {
+	case core.Command:
+		operation = resolved
+	case error:
+		return fmt.Errorf("operation %q: %w", c.message.OperationID, resolved)
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownOperation, c.message.OperationID)
+	}
+
+	err = checkArgs(c.message.OperationID, args)
+	if err != nil {
+		return err
+	}
+
+	if len(args) != 0 {
+		operation = command.NewMacroCommand(&setArgsCommand{object: target, args: args}, operation)
+	}
+
+	// The permission is checked before the order is queued and once more
+	// when it is executed, since the owner or the ACL may change meanwhile.
+	if c.message.PlayerID != "" {
+		check := command.NewCheckPermissionCommand(object.NewAdapter(target), c.message.PlayerID, permission(c.message.OperationID), operation)
+		err = check.Check()
+		if err != nil {
+			return err
+		}
+		operation = check
+	}
+
+	order := &OrderCommand{
+		message: c.message,
+		command: operation,
+		report:  c.report,
+	}
+	if q, ok := queue.(interface{ TryPut(core.Command) bool }); ok {
+		if !q.TryPut(order) {
+			return fmt.Errorf("%w: %q", ErrQueueFull, c.message.GameID)
+		}
+		return nil
+	}
+
+	queue.Put(order)
+	return nil
+}
+
+// OrderCommand is put into the game queue for every interpreted message so
+// that error handlers can tell which order a failed command came from.
+type OrderCommand struct {
+	message Message
+	command core.Command
+	report  func(err error)
+}
+
+func (c *OrderCommand) Message() Message {
+	return c.message
+}
+
+// Unwrap returns the command executing the order.
+func (c *OrderCommand) Unwrap() core.Command {
+	return c.command
+}
+
+func (c *OrderCommand) Execute() error {
+	err := c.command.Execute()
+	if c.report != nil {
+		c.report(err)
+	}
+	return err
+}
+
+type setArgsCommand struct {
+	object core.Object
+	args   map[string]interface{}
+}
+
+// Execute sets the args. A velocity is limited to the maximum speed of the
+// object, so an order cannot make the object faster than thrust can.
+func (c *setArgsCommand) Execute() error {
+	for key, value := range c.args {
+		if velocity, ok := value.(vector.Vector); ok && key == object.Velocity {
+			maxSpeed, err := object.NewAdapter(c.object).GetMaxSpeed()
+			if err != nil {
+				return err
+			}
+			value = command.LimitSpeed(velocity, maxSpeed)
+		}
+
+		err := c.object.SetProperty(key, value)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func convertArgs(args map[string]interface{}) (map[string]interface{}, error) {
+	result, err := object.ConvertProperties(args)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidArg, err)
+	}
+
+	return result, nil
+}
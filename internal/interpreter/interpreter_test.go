@@ -0,0 +1,247 @@
+package interpreter
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"modules/internal/command"
+	"modules/internal/core"
+	"modules/internal/ioc"
+	"modules/internal/object"
+	"modules/internal/vector"
+)
+
+type sliceQueue struct {
+	commands []core.Command
+}
+
+func (q *sliceQueue) Put(command core.Command) {
+	q.commands = append(q.commands, command)
+}
+
+func TestInterpreter(t *testing.T) {
+	suite.Run(t, new(InterpreterTestSuite))
+}
+
+type InterpreterTestSuite struct {
+	suite.Suite
+
+	queue   *sliceQueue
+	objects *object.Registry
+	ship    *object.Object
+}
+
+func (s *InterpreterTestSuite) SetupSuite() {
+	s.Require().NoError(RegisterOperations())
+
+	s.queue = &sliceQueue{}
+	s.objects = object.NewRegistry()
+
+	s.execute("Scopes.New", "interpreter_game")
+	s.execute("IoC.Register", "Game.Objects", func(params ...interface{}) interface{} {
+		return s.objects
+	})
+	s.execute("IoC.Register", "Game.Queue", func(params ...interface{}) interface{} {
+		return s.queue
+	})
+}
+
+func (s *InterpreterTestSuite) SetupTest() {
+	s.queue.commands = nil
+	s.ship = object.New(map[string]interface{}{
+		object.Position: vector.New([]int{12, 5}),
+		object.Velocity: vector.New([]int{-7, 3}),
+	})
+	s.Require().NoError(s.objects.Add("548", s.ship))
+}
+
+func (s *InterpreterTestSuite) TearDownTest() {
+	s.Require().NoError(s.objects.Remove("548"))
+}
+
+func (s *InterpreterTestSuite) execute(key string, params ...interface{}) {
+	err := ioc.Resolve(key, params...).(core.Command).Execute()
+	s.Require().NoError(err)
+}
+
+func (s *InterpreterTestSuite) message(data string) Message {
+	var message Message
+	s.Require().NoError(json.Unmarshal([]byte(data), &message))
+	return message
+}
+
+func (s *InterpreterTestSuite) TestMove() {
+	message := s.message(`{"game_id":"interpreter_game","object_id":"548","operation_id":"move","args":{"velocity":[1,2]}}`)
+
+	err := NewInterpretCommand(message).Execute()
+	s.Require().NoError(err)
+	s.Require().Len(s.queue.commands, 1)
+
+	err = s.queue.commands[0].Execute()
+	s.Require().NoError(err)
+
+	position, err := s.ship.GetProperty(object.Position)
+	s.Require().NoError(err)
+	s.Require().Equal(vector.New([]int{13, 7}), position)
+}
+
+func (s *InterpreterTestSuite) TestArgs() {
+	message := s.message(`{"game_id":"interpreter_game","object_id":"548","operation_id":"move","args":{"velocity":[1,2]}}`)
+
+	err := NewInterpretCommand(message).Execute()
+	s.Require().NoError(err)
+	s.Require().NoError(s.queue.commands[0].Execute())
+
+	s.Require().Equal(map[string]interface{}{
+		object.Position: vector.New([]int{13, 7}),
+		object.Velocity: vector.New([]int{1, 2}),
+	}, s.ship.Properties())
+}
+
+func (s *InterpreterTestSuite) TestSpeedArgs() {
+	s.Require().NoError(s.ship.SetProperty(object.Direction, 0))
+	s.Require().NoError(s.ship.SetProperty(object.DirectionsNumber, 8))
+	s.Require().NoError(s.ship.SetProperty(object.MaxSpeed, 5))
+
+	message := s.message(`{"game_id":"interpreter_game","object_id":"548","operation_id":"accelerate","args":{"thrust":3}}`)
+	s.Require().NoError(NewInterpretCommand(message).Execute())
+	s.Require().NoError(s.queue.commands[0].Execute())
+	s.Require().Equal(3, s.ship.Properties()[object.Thrust])
+	s.Require().Equal(vector.New([]int{-4, 3}), s.ship.Properties()[object.Velocity])
+
+	message = s.message(`{"game_id":"interpreter_game","object_id":"548","operation_id":"move","args":{"velocity":[30,40]}}`)
+	s.Require().NoError(NewInterpretCommand(message).Execute())
+	s.Require().NoError(s.queue.commands[1].Execute())
+	s.Require().Equal(vector.New([]int{3, 4}), s.ship.Properties()[object.Velocity])
+	s.Require().Equal(vector.New([]int{15, 9}), s.ship.Properties()[object.Position])
+}
+
+func (s *InterpreterTestSuite) TestForbiddenArgs() {
+	for _, args := range []string{
+		`{"owner":"bob"}`,
+		`{"acl":{"bob":["fire"]}}`,
+		`{"fuel":1000000}`,
+		`{"velocity":[1,2],"position":[0,0]}`,
+	} {
+		message := s.message(`{"game_id":"interpreter_game","object_id":"548","operation_id":"move","args":` + args + `}`)
+
+		err := NewInterpretCommand(message).Execute()
+		s.Require().ErrorIs(err, ErrInvalidArg, args)
+		s.Require().Empty(s.queue.commands)
+	}
+
+	message := s.message(`{"game_id":"interpreter_game","object_id":"548","operation_id":"rotate","args":{"velocity":[1,2]}}`)
+	s.Require().ErrorIs(NewInterpretCommand(message).Execute(), ErrInvalidArg)
+	s.Require().Equal(vector.New([]int{-7, 3}), s.ship.Properties()[object.Velocity])
+}
+
+func (s *InterpreterTestSuite) TestCallerScope() {
+	resolved := make(chan interface{})
+	go func() {
+		s.NoError(NewInterpretCommand(Message{GameID: "interpreter_game", ObjectID: "548", OperationID: "move"}).Execute())
+		resolved <- ioc.Resolve("Game.Queue")
+	}()
+
+	s.Require().Nil(<-resolved)
+	s.Require().Len(s.queue.commands, 1)
+}
+
+func (s *InterpreterTestSuite) TestUnknownGame() {
+	err := NewInterpretCommand(Message{GameID: "no_such_game", ObjectID: "548", OperationID: "move"}).Execute()
+	s.Require().ErrorIs(err, ErrUnknownGame)
+	s.Require().Empty(s.queue.commands)
+}
+
+func (s *InterpreterTestSuite) TestUnknownObject() {
+	err := NewInterpretCommand(Message{GameID: "interpreter_game", ObjectID: "549", OperationID: "move"}).Execute()
+	s.Require().ErrorIs(err, object.ErrUnknownObject)
+	s.Require().Empty(s.queue.commands)
+}
+
+func (s *InterpreterTestSuite) TestUnknownOperation() {
+	err := NewInterpretCommand(Message{GameID: "interpreter_game", ObjectID: "548", OperationID: "teleport"}).Execute()
+	s.Require().ErrorIs(err, ErrUnknownOperation)
+	s.Require().Empty(s.queue.commands)
+}
+
+func (s *InterpreterTestSuite) TestMissingDependency() {
+	err := NewInterpretCommand(Message{GameID: "interpreter_game", ObjectID: "548", OperationID: "start_move"}).Execute()
+	s.Require().ErrorIs(err, ErrMissingDependency)
+	s.Require().NotErrorIs(err, ErrUnknownOperation)
+	s.Require().Empty(s.queue.commands)
+}
+
+func (s *InterpreterTestSuite) TestInvalidArg() {
+	message := s.message(`{"game_id":"interpreter_game","object_id":"548","operation_id":"move","args":{"velocity":[1,"a"]}}`)
+
+	err := NewInterpretCommand(message).Execute()
+	s.Require().ErrorIs(err, ErrInvalidArg)
+	s.Require().Empty(s.queue.commands)
+}
+
+func (s *InterpreterTestSuite) TestReport() {
+	s.Require().NoError(s.ship.SetProperty(object.Velocity, "fast"))
+
+	var reported error
+	message := Message{GameID: "interpreter_game", ObjectID: "548", OperationID: "move"}
+	err := NewInterpretCommandWithReport(message, func(err error) {
+		reported = err
+	}).Execute()
+	s.Require().NoError(err)
+
+	err = s.queue.commands[0].Execute()
+	s.Require().ErrorIs(err, object.ErrInvalidProperty)
+	s.Require().ErrorIs(reported, object.ErrInvalidProperty)
+}
+
+func (s *InterpreterTestSuite) TestPermission() {
+	s.Require().NoError(s.ship.SetProperty(object.Owner, "alice"))
+	s.Require().NoError(s.ship.SetProperty(object.ACL, map[string][]string{"bob": {PermissionMove}}))
+
+	tests := []struct {
+		playerID    string
+		operationID string
+		err         error
+	}{
+		{"alice", "rotate", object.ErrNoProperty},
+		{"bob", "move_with_fuel", object.ErrNoProperty},
+		{"bob", "rotate", command.ErrPermissionDenied},
+		{"carol", "move", command.ErrPermissionDenied},
+		{"bob", "move", nil},
+	}
+
+	for _, test := range tests {
+		s.queue.commands = nil
+		interpret := NewInterpretCommand(Message{GameID: "interpreter_game", ObjectID: "548", OperationID: test.operationID})
+		interpret.SetPlayerID(test.playerID)
+		err := interpret.Execute()
+		if errors.Is(test.err, command.ErrPermissionDenied) {
+			s.Require().ErrorIs(err, test.err, test)
+			s.Require().Empty(s.queue.commands, test)
+			continue
+		}
+		s.Require().NoError(err, test)
+
+		err = s.queue.commands[0].Execute()
+		if test.err == nil {
+			s.Require().NoError(err, test)
+		} else {
+			s.Require().ErrorIs(err, test.err, test)
+		}
+	}
+
+	// An ACL changed after the order is queued is checked again.
+	s.queue.commands = nil
+	interpret := NewInterpretCommand(Message{GameID: "interpreter_game", ObjectID: "548", OperationID: "move"})
+	interpret.SetPlayerID("bob")
+	s.Require().NoError(interpret.Execute())
+	s.Require().NoError(s.ship.SetProperty(object.ACL, map[string][]string{}))
+	s.Require().ErrorIs(s.queue.commands[0].Execute(), command.ErrPermissionDenied)
+
+	position, err := s.ship.GetProperty(object.Position)
+	s.Require().NoError(err)
+	s.Require().Equal(vector.New([]int{5, 8}), position)
+}
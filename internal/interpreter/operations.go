@@ -0,0 +1,236 @@
+package interpreter
+
+import (
+	"fmt"
+	"slices"
+
+	"modules/internal/clock"
+	"modules/internal/collision"
+	"modules/internal/command"
+	"modules/internal/core"
+	"modules/internal/ioc"
+	"modules/internal/object"
+	"modules/internal/scheduler"
+	"modules/internal/vector"
+	"modules/internal/world"
+)
+
+// operations create the commands of the built-in operations. They fail with
+// ErrMissingDependency if the game scope lacks what an operation needs.
+var operations = map[string]func(target core.Object) (core.Command, error){
+	"move": func(target core.Object) (core.Command, error) {
+		return moveCommand(target), nil
+	},
+	"move_with_fuel": func(target core.Object) (core.Command, error) {
+		return afterMove(target, command.NewMoveWithFuelCommand(movableWithFuel{
+			Movable:      movable(target),
+			FuelBurnable: object.NewAdapter(target),
+		})), nil
+	},
+	"rotate": func(target core.Object) (core.Command, error) {
+		return command.NewRotateWithVelocityCommand(object.NewAdapter(target)), nil
+	},
+	"accelerate": func(target core.Object) (core.Command, error) {
+		return command.NewAccelerateCommand(object.NewAdapter(target)), nil
+	},
+	"thrust": func(target core.Object) (core.Command, error) {
+		return command.NewThrustCommand(object.NewAdapter(target)), nil
+	},
+	"start_move": func(target core.Object) (core.Command, error) {
+		s, ok := ioc.Resolve("Game.Scheduler").(*scheduler.Scheduler)
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrMissingDependency, "Game.Scheduler")
+		}
+		return scheduler.NewStartMoveCommand(s, target, moveCommand(target)), nil
+	},
+	"stop_move": func(target core.Object) (core.Command, error) {
+		s, ok := ioc.Resolve("Game.Scheduler").(*scheduler.Scheduler)
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrMissingDependency, "Game.Scheduler")
+		}
+		return scheduler.NewStopMoveCommand(s, target), nil
+	},
+	"begin_move": func(target core.Object) (core.Command, error) {
+		queue, ok := ioc.Resolve("Game.Queue").(core.Queue)
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrMissingDependency, "Game.Queue")
+		}
+		tokens, ok := ioc.Resolve("Game.Tokens").(*command.Tokens)
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrMissingDependency, "Game.Tokens")
+		}
+		return command.NewBeginMoveCommand(queue, tokens, target, moveCommand(target)), nil
+	},
+	"end_move": func(target core.Object) (core.Command, error) {
+		tokens, ok := ioc.Resolve("Game.Tokens").(*command.Tokens)
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrMissingDependency, "Game.Tokens")
+		}
+		return command.NewEndMoveCommand(tokens, target), nil
+	},
+	"fire": func(target core.Object) (core.Command, error) {
+		objects, ok := ioc.Resolve("Game.Objects").(*object.Registry)
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrMissingDependency, "Game.Objects")
+		}
+		s, ok := ioc.Resolve("Game.Scheduler").(*scheduler.Scheduler)
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrMissingDependency, "Game.Scheduler")
+		}
+		c, ok := ioc.Resolve("Clock").(clock.Clock)
+		if !ok {
+			c = clock.NewReal()
+		}
+		shooter := object.NewAdapter(target)
+		return command.NewShootCommand(shooter, c, projectileFactory(objects, s, shooter)), nil
+	},
+}
+
+func moveCommand(target core.Object) core.Command {
+	return afterMove(target, command.NewMoveCommand(movable(target)))
+}
+
+// movable keeps the object on "Game.Map" if the game has a map.
+func movable(target core.Object) core.Movable {
+	adapter := object.NewAdapter(target)
+	worldMap, ok := ioc.Resolve("Game.Map").(*world.Map)
+	if !ok {
+		return adapter
+	}
+	queue, ok := ioc.Resolve("Game.Queue").(core.Queue)
+	if !ok {
+		return adapter
+	}
+	destroy, ok := ioc.Resolve("Game.RemoveObject", target).(core.Command)
+	if !ok {
+		return adapter
+	}
+
+	return world.NewBoundedMovable(worldMap, adapter, queue, destroy)
+}
+
+type movableWithFuel struct {
+	core.Movable
+	core.FuelBurnable
+}
+
+func (m movableWithFuel) Unwrap() core.Movable {
+	return m.Movable
+}
+
+// afterMove makes the move command slow the object down by its drag and
+// update the position of the object in "Game.Collisions" if the game detects
+// collisions.
+func afterMove(target core.Object, move core.Command) core.Command {
+	adapter := object.NewAdapter(target)
+	commands := []core.Command{move, command.NewDragCommand(adapter)}
+	if detector, ok := ioc.Resolve("Game.Collisions").(*collision.Detector); ok {
+		commands = append(commands, collision.NewUpdateCommand(detector, target, adapter))
+	}
+
+	return command.NewMacroCommand(commands...)
+}
+
+// projectileFactory adds projectiles owned by the owner of the shooter to the
+// game objects. A projectile records the id of the shooter, so that it does
+// not hit the shooter even if nobody owns them. Projectiles move on the
+// scheduler ticks like after start_move.
+func projectileFactory(objects *object.Registry, s *scheduler.Scheduler, shooter *object.Adapter) command.ProjectileFactory {
+	return func(position, velocity vector.Vector) (core.Command, error) {
+		owner, err := shooter.GetOwner()
+		if err != nil {
+			return nil, err
+		}
+
+		properties := map[string]interface{}{
+			object.Kind:     object.KindProjectile,
+			object.Position: position,
+			object.Velocity: velocity,
+		}
+		if shot, ok := shooter.Object().(*object.Object); ok && shot.ID() != "" {
+			properties[object.Shooter] = shot.ID()
+		}
+		if owner != "" {
+			properties[object.Owner] = owner
+		}
+
+		projectile := object.New(properties)
+		objects.AddNew(object.KindProjectile+"-", projectile)
+		return scheduler.NewStartMoveCommand(s, projectile, moveCommand(projectile)), nil
+	}
+}
+
+const (
+	PermissionMove   = "move"
+	PermissionRotate = "rotate"
+	PermissionFire   = "fire"
+)
+
+// permissions maps operations onto the permissions checked against object
+// ACLs. Other operations need a permission named after them.
+var permissions = map[string]string{
+	"move":           PermissionMove,
+	"move_with_fuel": PermissionMove,
+	"accelerate":     PermissionMove,
+	"thrust":         PermissionMove,
+	"start_move":     PermissionMove,
+	"stop_move":      PermissionMove,
+	"begin_move":     PermissionMove,
+	"end_move":       PermissionMove,
+	"rotate":         PermissionRotate,
+	"fire":           PermissionFire,
+}
+
+// args lists the properties the args of an order may set for each operation.
+// The order may not touch any other property, e.g. the owner, ACL, fuel or
+// health of the object, since the permission checked for the operation does
+// not cover them. A velocity is limited to the maximum speed of the object.
+// Other operations take no args.
+var args = map[string][]string{
+	"move":           {object.Velocity},
+	"move_with_fuel": {object.Velocity},
+	"start_move":     {object.Velocity},
+	"begin_move":     {object.Velocity},
+	"rotate":         {object.AngularVelocity},
+	"accelerate":     {object.Thrust},
+	"thrust":         {object.Thrust},
+}
+
+func checkArgs(operationID string, values map[string]interface{}) error {
+	for key := range values {
+		if !slices.Contains(args[operationID], key) {
+			return fmt.Errorf("%w: %q is not an argument of %q", ErrInvalidArg, key, operationID)
+		}
+	}
+
+	return nil
+}
+
+func permission(operationID string) string {
+	if result, ok := permissions[operationID]; ok {
+		return result
+	}
+
+	return operationID
+}
+
+// RegisterOperations registers factories of the built-in operations as
+// "Operations.<operation_id>" in the current scope. A factory resolves to an
+// error if the operation cannot be created.
+func RegisterOperations() error {
+	for id, create := range operations {
+		create := create
+		err := ioc.Resolve("IoC.Register", "Operations."+id, func(params ...interface{}) interface{} {
+			result, err := create(params[0].(core.Object))
+			if err != nil {
+				return err
+			}
+			return result
+		}).(core.Command).Execute()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
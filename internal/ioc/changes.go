@@ -0,0 +1,176 @@
+package ioc
+
+type ChangeType uint8
+
+const (
+	Registered ChangeType = iota
+	Unregistered
+	Overridden
+	Restored
+)
+
+var changeTypeNames = []string{
+	"Registered",
+	"Unregistered",
+	"Overridden",
+	"Restored",
+}
+
+func (t ChangeType) String() string {
+	if int(t) < len(changeTypeNames) {
+		return changeTypeNames[t]
+	}
+
+	return "Unknown"
+}
+
+type ChangeHandler func(key string, change ChangeType)
+
+type unregisterCommand struct {
+	scope *scope
+	name  string
+}
+
+func (s *scope) newUnregisterCommand(params ...interface{}) interface{} {
+	return &unregisterCommand{
+		scope: s,
+		name:  params[0].(string),
+	}
+}
+
+func (c *unregisterCommand) Execute() error {
+	c.scope.mutex.Lock()
+	_, ok := c.scope.commandRegistry.LoadAndDelete(c.name)
+	delete(c.scope.overrides, c.name)
+	c.scope.mutex.Unlock()
+
+	if !ok {
+		return ErrNotRegistered
+	}
+
+	c.scope.notify(c.name, Unregistered)
+	return nil
+}
+
+// overrideCommand replaces a registration in a scope until it is undone.
+// Overrides of the same key stack: undoing one of them keeps the ones made
+// on top of it in place.
+type overrideCommand struct {
+	scope    *scope
+	name     string
+	create   func(params ...interface{}) interface{}
+	previous func(params ...interface{}) interface{}
+}
+
+func (s *scope) newOverrideCommand(params ...interface{}) interface{} {
+	return &overrideCommand{
+		scope:  s,
+		name:   params[0].(string),
+		create: params[1].(func(params ...interface{}) interface{}),
+	}
+}
+
+func (c *overrideCommand) Execute() error {
+	c.scope.mutex.Lock()
+	previous, ok := c.scope.commandRegistry.Load(c.name)
+	if ok {
+		c.previous = previous.(func(params ...interface{}) interface{})
+	} else {
+		c.previous = nil
+	}
+	c.scope.commandRegistry.Store(c.name, c.create)
+	c.scope.overrides[c.name] = append(c.scope.overrides[c.name], c)
+	c.scope.mutex.Unlock()
+
+	c.scope.notify(c.name, Overridden)
+	return nil
+}
+
+func (c *overrideCommand) Undo() error {
+	c.scope.mutex.Lock()
+	stack := c.scope.overrides[c.name]
+	index := -1
+	for i, override := range stack {
+		if override == c {
+			index = i
+			break
+		}
+	}
+
+	if index < 0 {
+		c.scope.mutex.Unlock()
+		return ErrNotOverridden
+	}
+
+	if index == len(stack)-1 {
+		if c.previous != nil {
+			c.scope.commandRegistry.Store(c.name, c.previous)
+		} else {
+			c.scope.commandRegistry.Delete(c.name)
+		}
+	} else {
+		stack[index+1].previous = c.previous
+	}
+
+	stack = append(stack[:index], stack[index+1:]...)
+	if len(stack) == 0 {
+		delete(c.scope.overrides, c.name)
+	} else {
+		c.scope.overrides[c.name] = stack
+	}
+	c.scope.mutex.Unlock()
+
+	c.scope.notify(c.name, Restored)
+	return nil
+}
+
+type subscribeCommand struct {
+	scope   *scope
+	name    string
+	handler ChangeHandler
+}
+
+func (s *scope) newSubscribeCommand(params ...interface{}) interface{} {
+	handler, ok := params[1].(ChangeHandler)
+	if !ok {
+		handler = params[1].(func(key string, change ChangeType))
+	}
+
+	return &subscribeCommand{
+		scope:   s,
+		name:    params[0].(string),
+		handler: handler,
+	}
+}
+
+func (c *subscribeCommand) Execute() error {
+	c.scope.mutex.Lock()
+	c.scope.subscribers[c.name] = append(c.scope.subscribers[c.name], c)
+	c.scope.mutex.Unlock()
+	return nil
+}
+
+func (c *subscribeCommand) Undo() error {
+	c.scope.mutex.Lock()
+	defer c.scope.mutex.Unlock()
+
+	subscribers := c.scope.subscribers[c.name]
+	for i, subscriber := range subscribers {
+		if subscriber == c {
+			c.scope.subscribers[c.name] = append(subscribers[:i:i], subscribers[i+1:]...)
+			return nil
+		}
+	}
+
+	return ErrNotSubscribed
+}
+
+func (s *scope) notify(name string, change ChangeType) {
+	s.mutex.Lock()
+	subscribers := s.subscribers[name]
+	s.mutex.Unlock()
+
+	for _, subscriber := range subscribers {
+		subscriber.handler(name, change)
+	}
+}
@@ -0,0 +1,120 @@
+package ioc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"modules/internal/core"
+)
+
+func TestChanges(t *testing.T) {
+	suite.Run(t, new(ChangesTestSuite))
+}
+
+type ChangesTestSuite struct {
+	suite.Suite
+}
+
+type change struct {
+	key    string
+	change ChangeType
+}
+
+func value(v interface{}) func(params ...interface{}) interface{} {
+	return func(params ...interface{}) interface{} {
+		return v
+	}
+}
+
+func (s *ChangesTestSuite) execute(key string, params ...interface{}) {
+	err := Resolve(key, params...).(core.Command).Execute()
+	s.Require().NoError(err)
+}
+
+func (s *ChangesTestSuite) TestUnregister() {
+	s.execute("Scopes.New", "changes_unregister_parent")
+	s.execute("IoC.Register", "rule", value("parent"))
+	s.execute("Scopes.New", "changes_unregister_child")
+	s.execute("IoC.Register", "rule", value("child"))
+	s.Require().Equal("child", Resolve("rule"))
+
+	s.execute("IoC.Unregister", "rule")
+	s.Require().Equal("parent", Resolve("rule"))
+
+	err := Resolve("IoC.Unregister", "rule").(core.Command).Execute()
+	s.Require().ErrorIs(err, ErrNotRegistered)
+}
+
+func (s *ChangesTestSuite) TestOverride() {
+	s.execute("Scopes.New", "changes_override")
+	s.execute("IoC.Register", "Commands.Move", value("move"))
+
+	override1 := Resolve("IoC.Override", "Commands.Move", value("special move 1")).(core.Undoable)
+	s.Require().NoError(override1.Execute())
+	s.Require().Equal("special move 1", Resolve("Commands.Move"))
+
+	override2 := Resolve("IoC.Override", "Commands.Move", value("special move 2")).(core.Undoable)
+	s.Require().NoError(override2.Execute())
+	s.Require().Equal("special move 2", Resolve("Commands.Move"))
+
+	s.Require().NoError(override1.Undo())
+	s.Require().Equal("special move 2", Resolve("Commands.Move"))
+
+	s.Require().NoError(override2.Undo())
+	s.Require().Equal("move", Resolve("Commands.Move"))
+
+	s.Require().ErrorIs(override2.Undo(), ErrNotOverridden)
+}
+
+func (s *ChangesTestSuite) TestOverrideMissingKey() {
+	s.execute("Scopes.New", "changes_override_missing")
+
+	override := Resolve("IoC.Override", "Commands.Move", value("special move")).(core.Undoable)
+	s.Require().NoError(override.Execute())
+	s.Require().Equal("special move", Resolve("Commands.Move"))
+
+	s.Require().NoError(override.Undo())
+	s.Require().Nil(Resolve("Commands.Move"))
+}
+
+func (s *ChangesTestSuite) TestRegisterDropsOverrides() {
+	s.execute("Scopes.New", "changes_register_over_override")
+	s.execute("IoC.Register", "Commands.Move", value("move"))
+
+	override := Resolve("IoC.Override", "Commands.Move", value("special move")).(core.Undoable)
+	s.Require().NoError(override.Execute())
+	s.execute("IoC.Register", "Commands.Move", value("new move"))
+
+	s.Require().ErrorIs(override.Undo(), ErrNotOverridden)
+	s.Require().Equal("new move", Resolve("Commands.Move"))
+}
+
+func (s *ChangesTestSuite) TestSubscribe() {
+	s.execute("Scopes.New", "changes_subscribe")
+
+	var changes []change
+	subscribe := Resolve("IoC.Subscribe", "Commands.Move", func(key string, c ChangeType) {
+		changes = append(changes, change{key, c})
+	}).(core.Undoable)
+	s.Require().NoError(subscribe.Execute())
+
+	s.execute("IoC.Register", "Commands.Move", value("move"))
+	s.execute("IoC.Register", "Commands.Rotate", value("rotate"))
+	override := Resolve("IoC.Override", "Commands.Move", value("special move")).(core.Undoable)
+	s.Require().NoError(override.Execute())
+	s.Require().NoError(override.Undo())
+	s.execute("IoC.Unregister", "Commands.Move")
+
+	s.Require().Equal([]change{
+		{"Commands.Move", Registered},
+		{"Commands.Move", Overridden},
+		{"Commands.Move", Restored},
+		{"Commands.Move", Unregistered},
+	}, changes)
+
+	s.Require().NoError(subscribe.Undo())
+	s.execute("IoC.Register", "Commands.Move", value("move"))
+	s.Require().Len(changes, 4)
+	s.Require().ErrorIs(subscribe.Undo(), ErrNotSubscribed)
+}
@@ -0,0 +1,11 @@
+package ioc
+
+import "fmt"
+
+var (
+	ErrNotRegistered = fmt.Errorf("key is not registered in scope")
+
+	ErrNotOverridden = fmt.Errorf("override is not active")
+
+	ErrNotSubscribed = fmt.Errorf("subscription is not active")
+)
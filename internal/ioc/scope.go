@@ -10,6 +10,10 @@ import (
 type scope struct {
 	commandRegistry *sync.Map
 	parent          *scope
+
+	mutex       sync.Mutex
+	overrides   map[string][]*overrideCommand
+	subscribers map[string][]*subscribeCommand
 }
 
 func (s *scope) resolve(key string, params ...interface{}) interface{} {
@@ -33,7 +37,12 @@ type registerCommand struct {
 }
 
 func (c *registerCommand) Execute() error {
+	c.scope.mutex.Lock()
 	c.scope.commandRegistry.Store(c.name, c.create)
+	delete(c.scope.overrides, c.name)
+	c.scope.mutex.Unlock()
+
+	c.scope.notify(c.name, Registered)
 	return nil
 }
 
@@ -55,6 +64,8 @@ func createScope(parent *scope) *scope {
 	result := &scope{
 		commandRegistry: &sync.Map{},
 		parent:          parent,
+		overrides:       map[string][]*overrideCommand{},
+		subscribers:     map[string][]*subscribeCommand{},
 	}
 
 	create := func(params ...interface{}) interface{} {
@@ -67,12 +78,15 @@ func createScope(parent *scope) *scope {
 		}
 	}
 	result.commandRegistry.Store("IoC.Register", create)
+	result.commandRegistry.Store("IoC.Unregister", result.newUnregisterCommand)
+	result.commandRegistry.Store("IoC.Override", result.newOverrideCommand)
+	result.commandRegistry.Store("IoC.Subscribe", result.newSubscribeCommand)
 
 	return result
 }
 
 type setScopeCommand struct {
-	goroutineID int64
+	goroutineID uint64
 	scopeName   string
 	createNew   bool
 }
@@ -96,7 +110,53 @@ func (c *setScopeCommand) Execute() error {
 	return ErrNoSuchScope
 }
 
-func getCurrentScope(gid int64) *scope {
+// inheritScopeCommand makes the scope of the goroutine that resolved
+// "Scopes.Inherit" current in the goroutine that executes it. Executed by the
+// resolving goroutine, it restores the scope that was current then. A nil
+// scope stands for the default one.
+type inheritScopeCommand struct {
+	scope *scope
+}
+
+func (c *inheritScopeCommand) Execute() error {
+	if c.scope == nil {
+		scopes.scopesByGID.Delete(routine.Goid())
+		return nil
+	}
+
+	scopes.scopesByGID.Store(routine.Goid(), c.scope)
+	return nil
+}
+
+// deleteScopeCommand forgets the name of the scope, so that "Scopes.New"
+// creates a new scope under the name. Goroutines the scope is current in
+// keep it.
+type deleteScopeCommand struct {
+	scopeName string
+}
+
+func (c *deleteScopeCommand) Execute() error {
+	_, ok := scopes.scopesByName.LoadAndDelete(c.scopeName)
+	if !ok {
+		return ErrNoSuchScope
+	}
+
+	return nil
+}
+
+// releaseScopeCommand forgets the current scope of the goroutine, which
+// falls back to the default scope. Goroutines that set a scope and end
+// release it, so that the scopes of ended goroutines are not kept.
+type releaseScopeCommand struct {
+	goroutineID uint64
+}
+
+func (c *releaseScopeCommand) Execute() error {
+	scopes.scopesByGID.Delete(c.goroutineID)
+	return nil
+}
+
+func getCurrentScope(gid uint64) *scope {
 	currentScope, ok := scopes.scopesByGID.Load(gid)
 	if ok {
 		return currentScope.(*scope)
@@ -121,6 +181,14 @@ func Resolve(key string, params ...interface{}) interface{} {
 			scopeName:   params[0].(string),
 			createNew:   false,
 		}
+	case "Scopes.Inherit":
+		current, _ := scopes.scopesByGID.Load(gid)
+		inherited, _ := current.(*scope)
+		return &inheritScopeCommand{scope: inherited}
+	case "Scopes.Delete":
+		return &deleteScopeCommand{scopeName: params[0].(string)}
+	case "Scopes.Release":
+		return &releaseScopeCommand{goroutineID: gid}
 	default:
 		currentScope := getCurrentScope(gid)
 		return currentScope.resolve(key, params...)
@@ -102,3 +102,93 @@ func (s *ScopeTestSuite) TestScopes() {
 	s.Require().NoError(err)
 	s.Require().Equal(30, a)
 }
+
+func (s *ScopeTestSuite) TestInherit() {
+	err := Resolve("Scopes.New", "inherited").(core.Command).Execute()
+	s.Require().NoError(err)
+
+	err = Resolve("IoC.Register", "inherited_value", func(params ...interface{}) interface{} {
+		return 10
+	}).(core.Command).Execute()
+	s.Require().NoError(err)
+
+	inherit := Resolve("Scopes.Inherit").(core.Command)
+	values := make(chan interface{})
+	go func() {
+		values <- Resolve("inherited_value")
+		s.Require().NoError(inherit.Execute())
+		values <- Resolve("inherited_value")
+	}()
+
+	s.Require().Nil(<-values)
+	s.Require().Equal(10, <-values)
+}
+
+func (s *ScopeTestSuite) TestInheritDefault() {
+	err := Resolve("Scopes.New", "left").(core.Command).Execute()
+	s.Require().NoError(err)
+	err = Resolve("IoC.Register", "left_value", func(params ...interface{}) interface{} {
+		return 10
+	}).(core.Command).Execute()
+	s.Require().NoError(err)
+
+	values := make(chan interface{})
+	go func() {
+		restore := Resolve("Scopes.Inherit").(core.Command)
+		s.NoError(Resolve("Scopes.Current", "left").(core.Command).Execute())
+		values <- Resolve("left_value")
+		s.NoError(restore.Execute())
+		values <- Resolve("left_value")
+	}()
+
+	s.Require().Equal(10, <-values)
+	s.Require().Nil(<-values)
+}
+
+func (s *ScopeTestSuite) TestDelete() {
+	err := Resolve("Scopes.New", "deleted").(core.Command).Execute()
+	s.Require().NoError(err)
+	err = Resolve("IoC.Register", "deleted_value", func(params ...interface{}) interface{} {
+		return 10
+	}).(core.Command).Execute()
+	s.Require().NoError(err)
+
+	s.Require().NoError(Resolve("Scopes.Delete", "deleted").(core.Command).Execute())
+	s.Require().ErrorIs(Resolve("Scopes.Delete", "deleted").(core.Command).Execute(), ErrNoSuchScope)
+	s.Require().Equal(10, Resolve("deleted_value"))
+	s.Require().ErrorIs(Resolve("Scopes.Current", "deleted").(core.Command).Execute(), ErrNoSuchScope)
+
+	s.Require().NoError(Resolve("Scopes.Release").(core.Command).Execute())
+	s.Require().NoError(Resolve("Scopes.New", "deleted").(core.Command).Execute())
+	s.Require().Nil(Resolve("deleted_value"))
+}
+
+func (s *ScopeTestSuite) TestRelease() {
+	count := func() (result int) {
+		scopes.scopesByGID.Range(func(key, value interface{}) bool {
+			result++
+			return true
+		})
+		return
+	}
+
+	err := Resolve("Scopes.New", "released").(core.Command).Execute()
+	s.Require().NoError(err)
+	before := count()
+
+	var wg sync.WaitGroup
+	for range 10 {
+		inherit := Resolve("Scopes.Inherit").(core.Command)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer Resolve("Scopes.Release").(core.Command).Execute()
+			s.NoError(inherit.Execute())
+		}()
+	}
+	wg.Wait()
+	s.Require().Equal(before, count())
+
+	s.Require().NoError(Resolve("Scopes.Release").(core.Command).Execute())
+	s.Require().Equal(before-1, count())
+}
@@ -0,0 +1,28 @@
+package journal
+
+import "fmt"
+
+var (
+	ErrNoSnapshot = fmt.Errorf("journal does not start with a snapshot")
+
+	ErrInvalidRecord = fmt.Errorf("invalid journal record")
+
+	ErrMismatch = fmt.Errorf("replay does not match the journal")
+
+	ErrUnencodable = fmt.Errorf("journal cannot encode the command")
+)
+
+// MismatchError is returned by Replay when the replayed command or state
+// differs from the journal. It matches ErrMismatch with errors.Is.
+type MismatchError struct {
+	Seq    uint64
+	Reason string
+}
+
+func (e *MismatchError) Error() string {
+	return fmt.Sprintf("%s at %d: %s", ErrMismatch, e.Seq, e.Reason)
+}
+
+func (e *MismatchError) Unwrap() error {
+	return ErrMismatch
+}
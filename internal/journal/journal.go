@@ -0,0 +1,163 @@
+package journal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"modules/internal/command"
+	"modules/internal/core"
+	"modules/internal/object"
+)
+
+const DefaultCheckpointInterval = 100
+
+const (
+	KindSnapshot   = "snapshot"
+	KindCommand    = "command"
+	KindCheckpoint = "checkpoint"
+)
+
+// Record is a line of the journal file. The journal starts with a snapshot
+// of the objects, then has a record for every executed command and a
+// checkpoint with the state of the objects after every few commands.
+type Record struct {
+	Kind    string       `json:"kind"`
+	Seq     uint64       `json:"seq"`
+	Command *command.DTO `json:"command,omitempty"`
+	Type    string       `json:"type,omitempty"`
+	Error   string       `json:"error,omitempty"`
+	Objects State        `json:"objects,omitempty"`
+}
+
+// State maps object ids onto object properties.
+type State map[string]map[string]interface{}
+
+// Journal writes the commands executed by a game as JSON lines. Record is
+// meant to be the after execute hook of the game listener, so the journal is
+// used by the listener goroutine only.
+type Journal struct {
+	writer             io.Writer
+	encoder            *json.Encoder
+	objects            *object.Registry
+	codec              *command.Codec
+	checkpointInterval int
+
+	seq uint64
+	err error
+}
+
+// New writes the snapshot of the objects and returns a journal writing a
+// checkpoint after every checkpointInterval commands.
+func New(writer io.Writer, objects *object.Registry, codec *command.Codec, checkpointInterval int) (*Journal, error) {
+	if checkpointInterval <= 0 {
+		checkpointInterval = DefaultCheckpointInterval
+	}
+
+	result := &Journal{
+		writer:             writer,
+		encoder:            json.NewEncoder(writer),
+		objects:            objects,
+		codec:              codec,
+		checkpointInterval: checkpointInterval,
+	}
+
+	err := result.write(Record{Kind: KindSnapshot, Objects: currentState(objects)})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Record appends the executed command and its outcome to the journal. A
+// command the codec does not know is recorded by the command it wraps if it
+// has an Unwrap method, e.g. an order. The journal fails for good on a
+// command it cannot encode, since it would not replay without it. Record
+// returns the error the journal fails with, once.
+func (j *Journal) Record(executed core.Command, err error) error {
+	if j.err != nil {
+		return nil
+	}
+
+	j.seq++
+	record := Record{
+		Kind: KindCommand,
+		Seq:  j.seq,
+		Type: fmt.Sprintf("%T", executed),
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+
+	dto, err := j.encode(executed)
+	if err != nil {
+		j.err = fmt.Errorf("%w: %d: %w", ErrUnencodable, j.seq, err)
+		return j.err
+	}
+	record.Command = &dto
+
+	err = j.write(record)
+	if err == nil && j.seq%uint64(j.checkpointInterval) == 0 {
+		err = j.Checkpoint()
+	}
+	return err
+}
+
+func (j *Journal) encode(executed core.Command) (command.DTO, error) {
+	for {
+		dto, err := j.codec.Encode(executed)
+		if err == nil {
+			return dto, nil
+		}
+
+		wrapper, ok := executed.(interface{ Unwrap() core.Command })
+		if !ok || !errors.Is(err, command.ErrUnknownCommand) {
+			return command.DTO{}, err
+		}
+		executed = wrapper.Unwrap()
+	}
+}
+
+// Checkpoint writes the current state of the objects.
+func (j *Journal) Checkpoint() error {
+	return j.write(Record{Kind: KindCheckpoint, Seq: j.seq, Objects: currentState(j.objects)})
+}
+
+// Close writes a final checkpoint and closes the writer if it is an
+// io.Closer. It returns the first error of writing the journal.
+func (j *Journal) Close() error {
+	_ = j.Checkpoint()
+	if closer, ok := j.writer.(io.Closer); ok {
+		err := closer.Close()
+		if j.err == nil {
+			j.err = err
+		}
+	}
+
+	return j.err
+}
+
+// write stops writing after the first error, the rest of the journal would
+// not replay anyway.
+func (j *Journal) write(record Record) error {
+	if j.err != nil {
+		return j.err
+	}
+
+	j.err = j.encoder.Encode(record)
+	return j.err
+}
+
+func currentState(objects *object.Registry) State {
+	result := State{}
+	for _, id := range objects.IDs() {
+		o, err := objects.Get(id)
+		if err == nil {
+			result[id] = o.Properties()
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,125 @@
+package journal
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"modules/internal/command"
+	"modules/internal/mock"
+	"modules/internal/object"
+	"modules/internal/queue"
+	"modules/internal/vector"
+)
+
+func TestJournal(t *testing.T) {
+	suite.Run(t, new(JournalTestSuite))
+}
+
+type JournalTestSuite struct {
+	suite.Suite
+
+	objects *object.Registry
+	ship    *object.Object
+	buffer  bytes.Buffer
+	journal *Journal
+}
+
+func (s *JournalTestSuite) SetupTest() {
+	s.objects = object.NewRegistry()
+	s.ship = object.New(map[string]interface{}{
+		object.Position:    vector.New([]int{12, 5}),
+		object.Velocity:    vector.New([]int{-7, 3}),
+		object.Fuel:        100,
+		object.Consumption: 70,
+		object.Owner:       "alice",
+	})
+	s.Require().NoError(s.objects.Add("548", s.ship))
+	s.buffer.Reset()
+
+	var err error
+	s.journal, err = New(&s.buffer, s.objects, command.NewCodec(s.objects), 2)
+	s.Require().NoError(err)
+}
+
+// execute executes the command and records it like the game listener.
+func (s *JournalTestSuite) execute(c interface{ Execute() error }) {
+	err := c.Execute()
+	s.Require().NoError(s.journal.Record(c, err))
+}
+
+func (s *JournalTestSuite) TestReplay() {
+	ship := object.NewAdapter(s.ship)
+	s.execute(command.NewMoveWithFuelCommand(ship))
+	s.execute(command.NewMoveWithFuelCommand(ship))
+	s.execute(command.NewRepeatingCommand(queue.NewListener(1).GetQueue(), command.NewMoveCommand(ship), command.NewCancellationToken()).WithMaxIterations(1))
+	s.execute(command.NewCheckPermissionCommand(ship, "bob", "move", command.NewMoveCommand(ship)))
+	s.Require().NoError(s.journal.Close())
+
+	lines := strings.Split(strings.TrimSpace(s.buffer.String()), "\n")
+	s.Require().Len(lines, 8)
+	s.Require().JSONEq(`{"kind": "command", "seq": 3, "type": "*command.RepeatingCommand", "command": {"name": "move", "object_id": "548"}}`, lines[4])
+
+	result, err := Replay(&s.buffer, nil)
+	s.Require().NoError(err)
+	s.Require().Equal(4, result.Commands)
+	s.Require().Equal(3, result.Checkpoints)
+
+	replayed, err := result.Objects.Get("548")
+	s.Require().NoError(err)
+	s.Require().Equal(s.ship.Properties(), replayed.Properties())
+}
+
+func (s *JournalTestSuite) TestUnencodable() {
+	s.execute(command.NewMoveCommand(object.NewAdapter(s.ship)))
+	noop := &mock.CommandMock{}
+	noop.On("Execute").Return(nil)
+	s.Require().ErrorIs(s.journal.Record(noop, noop.Execute()), ErrUnencodable)
+	s.Require().NoError(s.journal.Record(noop, nil))
+	s.Require().ErrorIs(s.journal.Close(), ErrUnencodable)
+
+	lines := strings.Split(strings.TrimSpace(s.buffer.String()), "\n")
+	s.Require().Len(lines, 2)
+
+	_, err := Replay(strings.NewReader(s.buffer.String()+`{"kind": "command", "seq": 2}`), nil)
+	s.Require().ErrorIs(err, ErrInvalidRecord)
+}
+
+func (s *JournalTestSuite) TestMismatch() {
+	s.execute(command.NewMoveCommand(object.NewAdapter(s.ship)))
+	s.Require().NoError(s.ship.SetProperty(object.Fuel, 1))
+	s.execute(command.NewMoveCommand(object.NewAdapter(s.ship)))
+	s.Require().NoError(s.journal.Close())
+
+	result, err := Replay(&s.buffer, nil)
+	s.Require().ErrorIs(err, ErrMismatch)
+	var mismatch *MismatchError
+	s.Require().ErrorAs(err, &mismatch)
+	s.Require().Equal(uint64(2), mismatch.Seq)
+	s.Require().Equal(2, result.Commands)
+}
+
+func (s *JournalTestSuite) TestOutcomeMismatch() {
+	s.execute(command.NewMoveCommand(object.NewAdapter(s.ship)))
+	s.Require().NoError(s.journal.Close())
+
+	journal := strings.Replace(s.buffer.String(), `"type"`, `"error":"some error","type"`, 1)
+	_, err := Replay(strings.NewReader(journal), nil)
+	s.Require().ErrorIs(err, ErrMismatch)
+}
+
+func (s *JournalTestSuite) TestInvalid() {
+	_, err := Replay(strings.NewReader(""), nil)
+	s.Require().ErrorIs(err, ErrNoSnapshot)
+
+	_, err = Replay(strings.NewReader(`{"kind": "checkpoint"}`), nil)
+	s.Require().ErrorIs(err, ErrNoSnapshot)
+
+	_, err = Replay(strings.NewReader(s.buffer.String()+"{\n"), nil)
+	s.Require().ErrorIs(err, ErrInvalidRecord)
+
+	_, err = Replay(strings.NewReader(s.buffer.String()+`{"kind": "teleport"}`), nil)
+	s.Require().ErrorIs(err, ErrInvalidRecord)
+}
@@ -0,0 +1,141 @@
+package journal
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+
+	"modules/internal/command"
+	"modules/internal/object"
+	"modules/internal/snapshot"
+)
+
+// Result sums up a replay.
+type Result struct {
+	Objects     *object.Registry
+	Commands    int
+	Checkpoints int
+}
+
+// Replay restores the objects from the snapshot the journal starts with and
+// executes the journaled commands on them in order, checking the outcome of
+// every command and the state of the objects at every checkpoint. The
+// commands are decoded with the codec newCodec creates for the restored
+// objects, if any, which knows the command types of other packages.
+func Replay(reader io.Reader, newCodec func(objects *object.Registry) (*command.Codec, error)) (*Result, error) {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(nil, 64*1024*1024)
+
+	var result *Result
+	var codec *command.Codec
+	for line := 1; scanner.Scan(); line++ {
+		var record Record
+		err := json.Unmarshal(scanner.Bytes(), &record)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %w", ErrInvalidRecord, line, err)
+		}
+
+		if result == nil {
+			if record.Kind != KindSnapshot {
+				return nil, ErrNoSnapshot
+			}
+
+			objects, err := snapshot.Snapshot{Objects: record.Objects}.Restore()
+			if err != nil {
+				return nil, err
+			}
+			result = &Result{Objects: objects}
+			codec = command.NewCodec(objects)
+			if newCodec != nil {
+				codec, err = newCodec(objects)
+				if err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+
+		switch record.Kind {
+		case KindCommand:
+			err = result.execute(codec, record)
+		case KindCheckpoint:
+			err = result.check(record)
+		default:
+			err = fmt.Errorf("%w: line %d: kind %q", ErrInvalidRecord, line, record.Kind)
+		}
+		if err != nil {
+			return result, err
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return result, err
+	}
+
+	if result == nil {
+		return nil, ErrNoSnapshot
+	}
+
+	return result, nil
+}
+
+func (r *Result) execute(codec *command.Codec, record Record) error {
+	if record.Command == nil {
+		return fmt.Errorf("%w: %d has no command", ErrInvalidRecord, record.Seq)
+	}
+
+	replayed, err := codec.Decode(*record.Command)
+	if err != nil {
+		return &MismatchError{Seq: record.Seq, Reason: err.Error()}
+	}
+
+	err = replayed.Execute()
+	r.Commands++
+	if (err != nil) != (record.Error != "") {
+		return &MismatchError{Seq: record.Seq, Reason: fmt.Sprintf("%s got error %v, recorded %q", record.Command.Name, err, record.Error)}
+	}
+
+	return nil
+}
+
+func (r *Result) check(record Record) error {
+	expected, err := normalize(record.Objects)
+	if err != nil {
+		return err
+	}
+
+	actual, err := normalize(currentState(r.Objects))
+	if err != nil {
+		return err
+	}
+
+	for id := range actual {
+		if _, ok := expected[id]; !ok {
+			return &MismatchError{Seq: record.Seq, Reason: fmt.Sprintf("object %q should be gone", id)}
+		}
+	}
+
+	for id, properties := range expected {
+		if !reflect.DeepEqual(properties, actual[id]) {
+			return &MismatchError{Seq: record.Seq, Reason: fmt.Sprintf("object %q is %v, recorded %v", id, actual[id], properties)}
+		}
+	}
+
+	r.Checkpoints++
+	return nil
+}
+
+// normalize gives the state the types it has once read back from JSON, so
+// that the recorded and the replayed state compare equal.
+func normalize(state State) (map[string]interface{}, error) {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]interface{}{}
+	err = json.Unmarshal(data, &result)
+	return result, err
+}
@@ -125,3 +125,90 @@ type RotatableMovableMock struct {
 	MovableMock
 	AcceleratingMock
 }
+
+type ControllableMock struct {
+	mock.Mock
+}
+
+func (m *ControllableMock) GetOwner() (string, error) {
+	args := m.Called()
+	return args.String(0), args.Error(1)
+}
+
+func (m *ControllableMock) GetACL() (map[string][]string, error) {
+	args := m.Called()
+	acl, _ := args.Get(0).(map[string][]string)
+	return acl, args.Error(1)
+}
+
+type MovableAcceleratingMock struct {
+	MovableMock
+	AcceleratingMock
+}
+
+type DamageableMock struct {
+	mock.Mock
+}
+
+func (m *DamageableMock) GetHealth() (int, error) {
+	args := m.Called()
+	return args.Int(0), args.Error(1)
+}
+
+func (m *DamageableMock) SetHealth(value int) error {
+	args := m.Called(value)
+	return args.Error(0)
+}
+
+func (m *DamageableMock) GetDamage() (int, error) {
+	args := m.Called()
+	return args.Int(0), args.Error(1)
+}
+
+type ThrustingMock struct {
+	mock.Mock
+}
+
+func (m *ThrustingMock) GetThrust() (int, error) {
+	args := m.Called()
+	return args.Int(0), args.Error(1)
+}
+
+func (m *ThrustingMock) GetMaxSpeed() (int, error) {
+	args := m.Called()
+	return args.Int(0), args.Error(1)
+}
+
+func (m *ThrustingMock) GetThrustConsumption() (int, error) {
+	args := m.Called()
+	return args.Int(0), args.Error(1)
+}
+
+func (m *ThrustingMock) GetDrag() (int, error) {
+	args := m.Called()
+	return args.Int(0), args.Error(1)
+}
+
+type ThrustableMock struct {
+	RotatableMock
+	MovableMock
+	AcceleratingMock
+	ThrustingMock
+	FuelBurnableMock
+}
+
+type UndoableMock struct {
+	CommandMock
+}
+
+func (c *UndoableMock) Undo() (err error) {
+	args := c.Called()
+
+	if undo, ok := args.Get(0).(func() error); ok {
+		err = undo()
+	} else {
+		err = args.Error(0)
+	}
+
+	return err
+}
@@ -0,0 +1,304 @@
+package object
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"modules/internal/core"
+	"modules/internal/vector"
+)
+
+const (
+	Position          = "position"
+	Velocity          = "velocity"
+	Direction         = "direction"
+	AngularVelocity   = "angular_velocity"
+	DirectionsNumber  = "directions_number"
+	Fuel              = "fuel"
+	Consumption       = "consumption"
+	Owner             = "owner"
+	ACL               = "acl"
+	Kind              = "kind"
+	Ammo              = "ammo"
+	Cooldown          = "cooldown"
+	LastShot          = "last_shot"
+	ProjectileSpeed   = "projectile_speed"
+	Radius            = "radius"
+	Health            = "health"
+	Damage            = "damage"
+	Thrust            = "thrust"
+	MaxSpeed          = "max_speed"
+	ThrustConsumption = "thrust_consumption"
+	Drag              = "drag"
+	Shooter           = "shooter"
+)
+
+const (
+	KindShip       = "ship"
+	KindProjectile = "projectile"
+	KindAsteroid   = "asteroid"
+)
+
+// Adapter exposes properties of a game object through the core interfaces
+// expected by commands.
+type Adapter struct {
+	object core.Object
+}
+
+func NewAdapter(object core.Object) *Adapter {
+	return &Adapter{
+		object: object,
+	}
+}
+
+// Object returns the adapted game object.
+func (a *Adapter) Object() core.Object {
+	return a.object
+}
+
+func (a *Adapter) GetPosition() (vector.Vector, error) {
+	return a.getVector(Position)
+}
+
+func (a *Adapter) GetVelocity() (vector.Vector, error) {
+	return a.getVector(Velocity)
+}
+
+func (a *Adapter) SetPosition(v vector.Vector) error {
+	return a.object.SetProperty(Position, v)
+}
+
+func (a *Adapter) SetVelocity(v vector.Vector) error {
+	return a.object.SetProperty(Velocity, v)
+}
+
+func (a *Adapter) GetDirection() (int, error) {
+	return a.getInt(Direction)
+}
+
+func (a *Adapter) GetAngularVelocity() (int, error) {
+	return a.getInt(AngularVelocity)
+}
+
+func (a *Adapter) SetDirection(direction int) error {
+	return a.object.SetProperty(Direction, direction)
+}
+
+func (a *Adapter) GetDirectionsNumber() (int, error) {
+	return a.getInt(DirectionsNumber)
+}
+
+func (a *Adapter) GetFuel() (int, error) {
+	return a.getInt(Fuel)
+}
+
+func (a *Adapter) GetConsumption() (int, error) {
+	return a.getInt(Consumption)
+}
+
+func (a *Adapter) SetFuel(fuel int) error {
+	return a.object.SetProperty(Fuel, fuel)
+}
+
+func (a *Adapter) GetAmmo() (int, error) {
+	return a.getInt(Ammo)
+}
+
+func (a *Adapter) SetAmmo(ammo int) error {
+	return a.object.SetProperty(Ammo, ammo)
+}
+
+// GetCooldown reads the cooldown in milliseconds, an object without one may
+// shoot every time.
+func (a *Adapter) GetCooldown() (time.Duration, error) {
+	milliseconds, err := a.getInt(Cooldown)
+	if errors.Is(err, ErrNoProperty) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(milliseconds) * time.Millisecond, nil
+}
+
+// GetLastShot returns the zero time for an object that has not shot yet. The
+// time is kept in Unix milliseconds so the properties stay plain numbers.
+func (a *Adapter) GetLastShot() (time.Time, error) {
+	milliseconds, err := a.getInt(LastShot)
+	if errors.Is(err, ErrNoProperty) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return time.UnixMilli(int64(milliseconds)), nil
+}
+
+func (a *Adapter) SetLastShot(t time.Time) error {
+	return a.object.SetProperty(LastShot, int(t.UnixMilli()))
+}
+
+func (a *Adapter) GetProjectileSpeed() (int, error) {
+	return a.getInt(ProjectileSpeed)
+}
+
+// GetRadius returns zero for an object without a radius, a point.
+func (a *Adapter) GetRadius() (int, error) {
+	return a.getOptionalInt(Radius)
+}
+
+func (a *Adapter) GetThrust() (int, error) {
+	return a.getInt(Thrust)
+}
+
+// GetMaxSpeed returns zero, no limit, for an object without a maximum speed.
+func (a *Adapter) GetMaxSpeed() (int, error) {
+	return a.getOptionalInt(MaxSpeed)
+}
+
+func (a *Adapter) GetThrustConsumption() (int, error) {
+	return a.getInt(ThrustConsumption)
+}
+
+// GetDrag returns the percentage of velocity lost on every move step, zero
+// for an object without a drag.
+func (a *Adapter) GetDrag() (int, error) {
+	return a.getOptionalInt(Drag)
+}
+
+// GetKind returns KindShip for an object without a kind.
+func (a *Adapter) GetKind() (string, error) {
+	value, err := a.object.GetProperty(Kind)
+	if errors.Is(err, ErrNoProperty) {
+		return KindShip, nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	result, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("%w: %q is %T, not a string", ErrInvalidProperty, Kind, value)
+	}
+
+	return result, nil
+}
+
+func (a *Adapter) GetHealth() (int, error) {
+	return a.getInt(Health)
+}
+
+func (a *Adapter) SetHealth(health int) error {
+	return a.object.SetProperty(Health, health)
+}
+
+// GetDamage returns 1 for an object without a damage, every hit counts.
+func (a *Adapter) GetDamage() (int, error) {
+	damage, err := a.getInt(Damage)
+	if errors.Is(err, ErrNoProperty) {
+		return 1, nil
+	}
+
+	return damage, err
+}
+
+// GetOwner returns an empty string for an object without an owner.
+func (a *Adapter) GetOwner() (string, error) {
+	return a.getOptionalString(Owner)
+}
+
+// GetShooter returns the id of the object that fired the projectile, or an
+// empty string for other objects.
+func (a *Adapter) GetShooter() (string, error) {
+	return a.getOptionalString(Shooter)
+}
+
+// GetACL returns the operations allowed to players other than the owner.
+func (a *Adapter) GetACL() (map[string][]string, error) {
+	value, err := a.object.GetProperty(ACL)
+	if errors.Is(err, ErrNoProperty) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch value := value.(type) {
+	case map[string][]string:
+		return value, nil
+	case map[string]interface{}:
+		result := make(map[string][]string, len(value))
+		for player, operations := range value {
+			if empty, ok := operations.(vector.Vector); ok && len(empty) == 0 {
+				continue
+			}
+
+			list, ok := operations.([]string)
+			if !ok {
+				return nil, fmt.Errorf("%w: %q of %q is %T, not a list of strings", ErrInvalidProperty, ACL, player, operations)
+			}
+			result[player] = list
+		}
+		return result, nil
+	default:
+		return nil, fmt.Errorf("%w: %q is %T, not a map", ErrInvalidProperty, ACL, value)
+	}
+}
+
+func (a *Adapter) getVector(key string) (vector.Vector, error) {
+	value, err := a.object.GetProperty(key)
+	if err != nil {
+		return nil, err
+	}
+
+	result, ok := value.(vector.Vector)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q is %T, not a vector", ErrInvalidProperty, key, value)
+	}
+
+	return result, nil
+}
+
+// getOptionalInt returns zero for an absent property.
+func (a *Adapter) getOptionalString(key string) (string, error) {
+	value, err := a.object.GetProperty(key)
+	if errors.Is(err, ErrNoProperty) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	result, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("%w: %q is %T, not a string", ErrInvalidProperty, key, value)
+	}
+
+	return result, nil
+}
+
+func (a *Adapter) getOptionalInt(key string) (int, error) {
+	result, err := a.getInt(key)
+	if errors.Is(err, ErrNoProperty) {
+		return 0, nil
+	}
+
+	return result, err
+}
+
+func (a *Adapter) getInt(key string) (int, error) {
+	value, err := a.object.GetProperty(key)
+	if err != nil {
+		return 0, err
+	}
+
+	result, ok := value.(int)
+	if !ok {
+		return 0, fmt.Errorf("%w: %q is %T, not an int", ErrInvalidProperty, key, value)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,80 @@
+package object
+
+import (
+	"fmt"
+
+	"modules/internal/vector"
+)
+
+// ConvertValue maps values decoded from JSON or YAML onto the types used by
+// adapters: whole numbers become int, lists of whole numbers become
+// vector.Vector and lists of strings become []string. Maps are converted
+// recursively, other values are returned unchanged.
+func ConvertValue(value interface{}) (interface{}, error) {
+	switch value := value.(type) {
+	case float64:
+		if value != float64(int(value)) {
+			return value, nil
+		}
+		return int(value), nil
+	case map[string]interface{}:
+		return ConvertProperties(value)
+	case []interface{}:
+		if strings, ok := toStrings(value); ok {
+			return strings, nil
+		}
+
+		values := make([]int, len(value))
+		for i, item := range value {
+			number, ok := toInt(item)
+			if !ok {
+				return nil, fmt.Errorf("%w: list items must be all integers or all strings", ErrInvalidProperty)
+			}
+			values[i] = number
+		}
+		return vector.New(values), nil
+	default:
+		return value, nil
+	}
+}
+
+func ConvertProperties(properties map[string]interface{}) (map[string]interface{}, error) {
+	result := make(map[string]interface{}, len(properties))
+	for key, value := range properties {
+		converted, err := ConvertValue(value)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", key, err)
+		}
+		result[key] = converted
+	}
+
+	return result, nil
+}
+
+func toStrings(values []interface{}) ([]string, bool) {
+	if len(values) == 0 {
+		return nil, false
+	}
+
+	result := make([]string, len(values))
+	for i, value := range values {
+		s, ok := value.(string)
+		if !ok {
+			return nil, false
+		}
+		result[i] = s
+	}
+
+	return result, true
+}
+
+func toInt(value interface{}) (int, bool) {
+	switch value := value.(type) {
+	case int:
+		return value, true
+	case float64:
+		return int(value), value == float64(int(value))
+	default:
+		return 0, false
+	}
+}
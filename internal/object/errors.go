@@ -0,0 +1,13 @@
+package object
+
+import "fmt"
+
+var (
+	ErrNoProperty = fmt.Errorf("no such property")
+
+	ErrInvalidProperty = fmt.Errorf("invalid property type")
+
+	ErrUnknownObject = fmt.Errorf("unknown object")
+
+	ErrDuplicateObject = fmt.Errorf("duplicate object")
+)
@@ -0,0 +1,213 @@
+package object
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+type Object struct {
+	mutex      sync.RWMutex
+	properties map[string]interface{}
+	id         string
+}
+
+func New(properties map[string]interface{}) *Object {
+	result := &Object{
+		properties: map[string]interface{}{},
+	}
+	for key, value := range properties {
+		result.properties[key] = value
+	}
+
+	return result
+}
+
+func (o *Object) GetProperty(key string) (interface{}, error) {
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+
+	value, ok := o.properties[key]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrNoProperty, key)
+	}
+
+	return value, nil
+}
+
+func (o *Object) SetProperty(key string, value interface{}) error {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.properties[key] = value
+	return nil
+}
+
+// ID returns the id the object has last been added to a registry under. The
+// object keeps it once removed, so that the commands still holding the
+// object can tell which one it was.
+func (o *Object) ID() string {
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+
+	return o.id
+}
+
+func (o *Object) setID(id string) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.id = id
+}
+
+func (o *Object) Properties() map[string]interface{} {
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+
+	result := make(map[string]interface{}, len(o.properties))
+	for key, value := range o.properties {
+		result[key] = value
+	}
+
+	return result
+}
+
+type Registry struct {
+	mutex   sync.RWMutex
+	objects map[string]*Object
+	ids     map[*Object]string
+	players map[string]struct{}
+	lastID  int
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		objects: map[string]*Object{},
+		ids:     map[*Object]string{},
+		players: map[string]struct{}{},
+	}
+}
+
+func (r *Registry) Add(id string, object *Object) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, ok := r.objects[id]; ok {
+		return fmt.Errorf("%w: %q", ErrDuplicateObject, id)
+	}
+
+	r.objects[id] = object
+	r.ids[object] = id
+	object.setID(id)
+	return nil
+}
+
+// AddNew adds the object under a new id made of the prefix and a number and
+// returns the id.
+func (r *Registry) AddNew(prefix string, object *Object) string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for {
+		r.lastID++
+		id := prefix + strconv.Itoa(r.lastID)
+		if _, ok := r.objects[id]; !ok {
+			r.objects[id] = object
+			r.ids[object] = id
+			object.setID(id)
+			return id
+		}
+	}
+}
+
+func (r *Registry) Get(id string) (*Object, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	object, ok := r.objects[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownObject, id)
+	}
+
+	return object, nil
+}
+
+func (r *Registry) Remove(id string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	object, ok := r.objects[id]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownObject, id)
+	}
+
+	delete(r.objects, id)
+	delete(r.ids, object)
+	return nil
+}
+
+// ID returns the id the object has been added under.
+func (r *Registry) ID(object *Object) (string, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	id, ok := r.ids[object]
+	return id, ok
+}
+
+// SetOwner makes the player the owner of the objects and adds the player to
+// the players, even if it owns no objects.
+func (r *Registry) SetOwner(playerID string, ids ...string) error {
+	r.AddPlayer(playerID)
+
+	for _, id := range ids {
+		o, err := r.Get(id)
+		if err != nil {
+			return err
+		}
+
+		err = o.SetProperty(Owner, playerID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AddPlayer adds the players of the game the objects belong to.
+func (r *Registry) AddPlayer(playerIDs ...string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, playerID := range playerIDs {
+		r.players[playerID] = struct{}{}
+	}
+}
+
+func (r *Registry) Players() []string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	result := make([]string, 0, len(r.players))
+	for playerID := range r.players {
+		result = append(result, playerID)
+	}
+	sort.Strings(result)
+
+	return result
+}
+
+func (r *Registry) IDs() []string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	result := make([]string, 0, len(r.objects))
+	for id := range r.objects {
+		result = append(result, id)
+	}
+	sort.Strings(result)
+
+	return result
+}
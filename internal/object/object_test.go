@@ -0,0 +1,183 @@
+package object
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"modules/internal/vector"
+)
+
+func TestObject(t *testing.T) {
+	suite.Run(t, new(ObjectTestSuite))
+}
+
+type ObjectTestSuite struct {
+	suite.Suite
+
+	object  *Object
+	adapter *Adapter
+}
+
+func (s *ObjectTestSuite) SetupTest() {
+	s.object = New(map[string]interface{}{
+		Position:  vector.New([]int{12, 5}),
+		Direction: 7,
+		Fuel:      "full",
+	})
+	s.adapter = NewAdapter(s.object)
+}
+
+func (s *ObjectTestSuite) TestAdapter() {
+	position, err := s.adapter.GetPosition()
+	s.Require().NoError(err)
+	s.Require().Equal(vector.New([]int{12, 5}), position)
+
+	direction, err := s.adapter.GetDirection()
+	s.Require().NoError(err)
+	s.Require().Equal(7, direction)
+
+	s.Require().NoError(s.adapter.SetVelocity(vector.New([]int{1, 1})))
+	velocity, err := s.object.GetProperty(Velocity)
+	s.Require().NoError(err)
+	s.Require().Equal(vector.New([]int{1, 1}), velocity)
+}
+
+func (s *ObjectTestSuite) TestAdapterErrors() {
+	_, err := s.adapter.GetAngularVelocity()
+	s.Require().ErrorIs(err, ErrNoProperty)
+
+	_, err = s.adapter.GetFuel()
+	s.Require().ErrorIs(err, ErrInvalidProperty)
+
+	s.Require().NoError(s.object.SetProperty(Velocity, 3))
+	_, err = s.adapter.GetVelocity()
+	s.Require().ErrorIs(err, ErrInvalidProperty)
+}
+
+func (s *ObjectTestSuite) TestRegistry() {
+	registry := NewRegistry()
+	s.Require().NoError(registry.Add("548", s.object))
+	s.Require().NoError(registry.Add("12", New(nil)))
+	s.Require().ErrorIs(registry.Add("548", New(nil)), ErrDuplicateObject)
+
+	object, err := registry.Get("548")
+	s.Require().NoError(err)
+	s.Require().Same(s.object, object)
+	s.Require().Equal([]string{"12", "548"}, registry.IDs())
+
+	s.Require().NoError(registry.Remove("548"))
+	_, err = registry.Get("548")
+	s.Require().ErrorIs(err, ErrUnknownObject)
+	s.Require().ErrorIs(registry.Remove("548"), ErrUnknownObject)
+
+	s.Require().NoError(registry.Add("shot-2", New(nil)))
+	s.Require().Equal("shot-1", registry.AddNew("shot-", s.object))
+	s.Require().Equal("shot-3", registry.AddNew("shot-", New(nil)))
+
+	id, ok := registry.ID(s.object)
+	s.Require().True(ok)
+	s.Require().Equal("shot-1", id)
+	s.Require().NoError(registry.Remove("shot-1"))
+	_, ok = registry.ID(s.object)
+	s.Require().False(ok)
+	s.Require().Equal("shot-1", s.object.ID())
+}
+
+func (s *ObjectTestSuite) TestShootable() {
+	lastShot, err := s.adapter.GetLastShot()
+	s.Require().NoError(err)
+	s.Require().True(lastShot.IsZero())
+	cooldown, err := s.adapter.GetCooldown()
+	s.Require().NoError(err)
+	s.Require().Zero(cooldown)
+
+	now := time.UnixMilli(1700000000123)
+	s.Require().NoError(s.adapter.SetLastShot(now))
+	s.Require().NoError(s.object.SetProperty(Cooldown, 250))
+	lastShot, err = s.adapter.GetLastShot()
+	s.Require().NoError(err)
+	s.Require().True(now.Equal(lastShot))
+	cooldown, err = s.adapter.GetCooldown()
+	s.Require().NoError(err)
+	s.Require().Equal(250*time.Millisecond, cooldown)
+
+	_, err = s.adapter.GetAmmo()
+	s.Require().ErrorIs(err, ErrNoProperty)
+}
+
+func (s *ObjectTestSuite) TestRadius() {
+	radius, err := s.adapter.GetRadius()
+	s.Require().NoError(err)
+	s.Require().Zero(radius)
+
+	s.Require().NoError(s.object.SetProperty(Radius, "big"))
+	_, err = s.adapter.GetRadius()
+	s.Require().ErrorIs(err, ErrInvalidProperty)
+}
+
+func (s *ObjectTestSuite) TestThrust() {
+	maxSpeed, err := s.adapter.GetMaxSpeed()
+	s.Require().NoError(err)
+	s.Require().Zero(maxSpeed)
+	drag, err := s.adapter.GetDrag()
+	s.Require().NoError(err)
+	s.Require().Zero(drag)
+	_, err = s.adapter.GetThrust()
+	s.Require().ErrorIs(err, ErrNoProperty)
+	_, err = s.adapter.GetThrustConsumption()
+	s.Require().ErrorIs(err, ErrNoProperty)
+
+	s.Require().NoError(s.object.SetProperty(Thrust, 3))
+	s.Require().NoError(s.object.SetProperty(Drag, 5))
+	thrust, err := s.adapter.GetThrust()
+	s.Require().NoError(err)
+	s.Require().Equal(3, thrust)
+	drag, err = s.adapter.GetDrag()
+	s.Require().NoError(err)
+	s.Require().Equal(5, drag)
+}
+
+func (s *ObjectTestSuite) TestOwner() {
+	owner, err := s.adapter.GetOwner()
+	s.Require().NoError(err)
+	s.Require().Empty(owner)
+	acl, err := s.adapter.GetACL()
+	s.Require().NoError(err)
+	s.Require().Nil(acl)
+
+	registry := NewRegistry()
+	s.Require().NoError(registry.Add("548", s.object))
+	s.Require().NoError(registry.SetOwner("alice", "548"))
+	s.Require().ErrorIs(registry.SetOwner("alice", "549"), ErrUnknownObject)
+	owner, err = s.adapter.GetOwner()
+	s.Require().NoError(err)
+	s.Require().Equal("alice", owner)
+	s.Require().NoError(registry.SetOwner("bob"))
+	registry.AddPlayer("carol")
+	s.Require().Equal([]string{"alice", "bob", "carol"}, registry.Players())
+
+	properties, err := ConvertProperties(map[string]interface{}{
+		ACL: map[string]interface{}{
+			"bob":   []interface{}{"move", "fire"},
+			"carol": []interface{}{},
+		},
+	})
+	s.Require().NoError(err)
+	s.Require().NoError(s.object.SetProperty(ACL, properties[ACL]))
+	acl, err = s.adapter.GetACL()
+	s.Require().NoError(err)
+	s.Require().Equal(map[string][]string{"bob": {"move", "fire"}}, acl)
+
+	s.Require().NoError(s.object.SetProperty(ACL, map[string]interface{}{"bob": 1}))
+	_, err = s.adapter.GetACL()
+	s.Require().ErrorIs(err, ErrInvalidProperty)
+
+	s.Require().NoError(s.object.SetProperty(Owner, 1))
+	_, err = s.adapter.GetOwner()
+	s.Require().ErrorIs(err, ErrInvalidProperty)
+
+	_, err = ConvertValue([]interface{}{"move", 1})
+	s.Require().ErrorIs(err, ErrInvalidProperty)
+}
@@ -0,0 +1,11 @@
+package plugin
+
+import "fmt"
+
+var (
+	ErrDuplicatePlugin = fmt.Errorf("duplicate plugin")
+
+	ErrMissingDependency = fmt.Errorf("missing dependency")
+
+	ErrDependencyCycle = fmt.Errorf("dependency cycle")
+)
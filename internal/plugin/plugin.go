@@ -0,0 +1,189 @@
+package plugin
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"modules/internal/core"
+	"modules/internal/ioc"
+)
+
+type Plugin interface {
+	Name() string
+	Dependencies() []string
+	Load(scope Scope) error
+}
+
+// Scope collects registrations of a plugin. They reach ioc only after
+// Load returns without an error.
+type Scope interface {
+	Name() string
+	Register(key string, create func(params ...interface{}) interface{})
+}
+
+type Registry struct {
+	mutex   sync.Mutex
+	plugins map[string]Plugin
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		plugins: map[string]Plugin{},
+	}
+}
+
+var defaultRegistry = NewRegistry()
+
+// Register adds a plugin to the registry of plugins compiled into the
+// binary. It is meant to be called from init functions of plugin packages.
+func Register(p Plugin) {
+	err := defaultRegistry.Register(p)
+	if err != nil {
+		panic(err)
+	}
+}
+
+func LoadAll(scopeName string) error {
+	return defaultRegistry.LoadAll(scopeName)
+}
+
+func (r *Registry) Register(p Plugin) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, ok := r.plugins[p.Name()]; ok {
+		return fmt.Errorf("%w: %q", ErrDuplicatePlugin, p.Name())
+	}
+
+	r.plugins[p.Name()] = p
+	return nil
+}
+
+func (r *Registry) LoadAll(scopeName string) error {
+	ordered, err := r.ordered()
+	if err != nil {
+		return err
+	}
+
+	for _, p := range ordered {
+		err = load(p, scopeName)
+		if err != nil {
+			return fmt.Errorf("plugin %q: %w", p.Name(), err)
+		}
+	}
+
+	return nil
+}
+
+func (r *Registry) ordered() ([]Plugin, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	names := make([]string, 0, len(r.plugins))
+	for name := range r.plugins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := map[string]int{}
+	var result []Plugin
+
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("%w: %q", ErrDependencyCycle, name)
+		}
+
+		state[name] = visiting
+		p := r.plugins[name]
+		for _, dependency := range p.Dependencies() {
+			if _, ok := r.plugins[dependency]; !ok {
+				return fmt.Errorf("%w: %q required by %q", ErrMissingDependency, dependency, name)
+			}
+
+			err := visit(dependency)
+			if err != nil {
+				return err
+			}
+		}
+		state[name] = visited
+
+		result = append(result, p)
+		return nil
+	}
+
+	for _, name := range names {
+		err := visit(name)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+type registration struct {
+	key    string
+	create func(params ...interface{}) interface{}
+}
+
+type pendingScope struct {
+	name          string
+	registrations []registration
+}
+
+func (s *pendingScope) Name() string {
+	return s.name
+}
+
+func (s *pendingScope) Register(key string, create func(params ...interface{}) interface{}) {
+	s.registrations = append(s.registrations, registration{
+		key:    key,
+		create: create,
+	})
+}
+
+func load(p Plugin, scopeName string) error {
+	scope := &pendingScope{
+		name: scopeName,
+	}
+
+	err := p.Load(scope)
+	if err != nil {
+		return err
+	}
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- scope.apply()
+	}()
+
+	return <-errChan
+}
+
+func (s *pendingScope) apply() error {
+	if s.name != "" {
+		err := ioc.Resolve("Scopes.Current", s.name).(core.Command).Execute()
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, r := range s.registrations {
+		err := ioc.Resolve("IoC.Register", r.key, r.create).(core.Command).Execute()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,155 @@
+package plugin
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"modules/internal/core"
+	"modules/internal/ioc"
+)
+
+type testPlugin struct {
+	name         string
+	dependencies []string
+	keys         []string
+	err          error
+	loaded       *[]string
+}
+
+func (p *testPlugin) Name() string {
+	return p.name
+}
+
+func (p *testPlugin) Dependencies() []string {
+	return p.dependencies
+}
+
+func (p *testPlugin) Load(scope Scope) error {
+	for _, key := range p.keys {
+		value := p.name
+		scope.Register(key, func(params ...interface{}) interface{} {
+			return value
+		})
+	}
+
+	if p.loaded != nil {
+		*p.loaded = append(*p.loaded, p.name)
+	}
+
+	return p.err
+}
+
+func TestPlugin(t *testing.T) {
+	suite.Run(t, new(PluginTestSuite))
+}
+
+type PluginTestSuite struct {
+	suite.Suite
+
+	registry *Registry
+	loaded   []string
+}
+
+func (s *PluginTestSuite) SetupTest() {
+	s.registry = NewRegistry()
+	s.loaded = nil
+}
+
+func (s *PluginTestSuite) newScope(name string) {
+	err := ioc.Resolve("Scopes.New", name).(core.Command).Execute()
+	s.Require().NoError(err)
+}
+
+func (s *PluginTestSuite) TestDependencyOrder() {
+	s.newScope("plugin_order")
+
+	s.Require().NoError(s.registry.Register(&testPlugin{
+		name:         "a",
+		dependencies: []string{"c"},
+		keys:         []string{"Plugins.A"},
+		loaded:       &s.loaded,
+	}))
+	s.Require().NoError(s.registry.Register(&testPlugin{
+		name:         "b",
+		dependencies: []string{"a", "c"},
+		keys:         []string{"Plugins.B"},
+		loaded:       &s.loaded,
+	}))
+	s.Require().NoError(s.registry.Register(&testPlugin{
+		name:   "c",
+		keys:   []string{"Plugins.C"},
+		loaded: &s.loaded,
+	}))
+
+	err := s.registry.LoadAll("plugin_order")
+	s.Require().NoError(err)
+	s.Require().Equal([]string{"c", "a", "b"}, s.loaded)
+	s.Require().Equal("a", ioc.Resolve("Plugins.A"))
+	s.Require().Equal("b", ioc.Resolve("Plugins.B"))
+	s.Require().Equal("c", ioc.Resolve("Plugins.C"))
+}
+
+func (s *PluginTestSuite) TestFailedPlugin() {
+	s.newScope("plugin_failed")
+	errLoad := fmt.Errorf("load error")
+
+	s.Require().NoError(s.registry.Register(&testPlugin{
+		name: "ok",
+		keys: []string{"Plugins.Ok"},
+	}))
+	s.Require().NoError(s.registry.Register(&testPlugin{
+		name:         "broken",
+		dependencies: []string{"ok"},
+		keys:         []string{"Plugins.Broken1", "Plugins.Broken2"},
+		err:          errLoad,
+	}))
+
+	err := s.registry.LoadAll("plugin_failed")
+	s.Require().ErrorIs(err, errLoad)
+	s.Require().Equal("ok", ioc.Resolve("Plugins.Ok"))
+	s.Require().Nil(ioc.Resolve("Plugins.Broken1"))
+	s.Require().Nil(ioc.Resolve("Plugins.Broken2"))
+}
+
+func (s *PluginTestSuite) TestMissingDependency() {
+	s.Require().NoError(s.registry.Register(&testPlugin{
+		name:         "a",
+		dependencies: []string{"missing"},
+	}))
+
+	err := s.registry.LoadAll("")
+	s.Require().ErrorIs(err, ErrMissingDependency)
+}
+
+func (s *PluginTestSuite) TestDependencyCycle() {
+	s.Require().NoError(s.registry.Register(&testPlugin{
+		name:         "a",
+		dependencies: []string{"b"},
+		keys:         []string{"Plugins.Cycle"},
+	}))
+	s.Require().NoError(s.registry.Register(&testPlugin{
+		name:         "b",
+		dependencies: []string{"a"},
+	}))
+
+	err := s.registry.LoadAll("")
+	s.Require().ErrorIs(err, ErrDependencyCycle)
+	s.Require().Nil(ioc.Resolve("Plugins.Cycle"))
+}
+
+func (s *PluginTestSuite) TestDuplicate() {
+	s.Require().NoError(s.registry.Register(&testPlugin{name: "a"}))
+	s.Require().ErrorIs(s.registry.Register(&testPlugin{name: "a"}), ErrDuplicatePlugin)
+}
+
+func (s *PluginTestSuite) TestUnknownScope() {
+	s.Require().NoError(s.registry.Register(&testPlugin{
+		name: "a",
+		keys: []string{"Plugins.UnknownScope"},
+	}))
+
+	err := s.registry.LoadAll("plugin_no_such_scope")
+	s.Require().ErrorIs(err, ioc.ErrNoSuchScope)
+}
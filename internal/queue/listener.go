@@ -2,6 +2,7 @@ package queue
 
 import (
 	"context"
+	"sync"
 
 	"modules/internal/core"
 )
@@ -13,8 +14,12 @@ type Listener struct {
 	cancel       func()
 	commandsChan chan core.Command
 	aliveChan    chan int
-	queue        core.Queue
+	doneChan     chan struct{}
+	queue        *Queue
 	errorHandler core.ErrorHandler
+	afterExecute core.ErrorHandler
+	aliveOnce    sync.Once
+	commandsOnce sync.Once
 }
 
 func NewListener(bufferLength int) *Listener {
@@ -30,8 +35,10 @@ func NewListener(bufferLength int) *Listener {
 		cancel:       cancel,
 		commandsChan: commandsChan,
 		aliveChan:    aliveChan,
+		doneChan:     make(chan struct{}),
 		queue:        queue,
 		errorHandler: noOpErrorHandler,
+		afterExecute: noOpErrorHandler,
 	}
 
 	return listener
@@ -45,7 +52,20 @@ func (l *Listener) SetErrorHandler(errorHandler core.ErrorHandler) {
 	l.errorHandler = errorHandler
 }
 
+// SetAfterExecute sets a hook called in the listener goroutine after every
+// command, with the error returned by the command or nil. It is called after
+// the error handler.
+func (l *Listener) SetAfterExecute(hook core.ErrorHandler) {
+	l.afterExecute = hook
+}
+
+func (l *Listener) Done() <-chan struct{} {
+	return l.doneChan
+}
+
 func (l *Listener) run() {
+	defer close(l.doneChan)
+
 	for {
 		select {
 		case <-l.ctx.Done():
@@ -61,6 +81,7 @@ func (l *Listener) run() {
 			if err != nil {
 				l.errorHandler(command, err)
 			}
+			l.afterExecute(command, err)
 
 			if l.ctx.Err() != nil {
 				// hard stop
@@ -71,12 +92,13 @@ func (l *Listener) run() {
 }
 
 func (l *Listener) SoftStop() {
-	close(l.aliveChan)
-	close(l.commandsChan)
+	l.aliveOnce.Do(func() { close(l.aliveChan) })
+	l.commandsOnce.Do(l.queue.close)
 }
 
+// HardStop may follow SoftStop to drop the commands still left in the queue.
 func (l *Listener) HardStop() {
-	close(l.aliveChan)
+	l.aliveOnce.Do(func() { close(l.aliveChan) })
 	l.cancel()
 }
 
@@ -1,15 +1,17 @@
 package queue
 
 import (
-	"runtime"
+	"fmt"
 	"testing"
-	"time"
 
 	"github.com/stretchr/testify/suite"
 
+	"modules/internal/core"
 	"modules/internal/mock"
 )
 
+var errSomeError = fmt.Errorf("some error")
+
 func TestListener(t *testing.T) {
 	suite.Run(t, new(ListenerTestSuite))
 }
@@ -74,14 +76,8 @@ func (s *ListenerTestSuite) TestHardStop() {
 
 	close(executeChan1Go)
 
-	runtime.Gosched()
-	select {
-	case <-executeChan2:
-		// should not get here
-		s.Require().False(true)
-	case <-time.After(time.Millisecond * 10):
-	}
-
+	// the listener returns right after the running command
+	<-listener.Done()
 	s.Require().False(executeStarted2)
 }
 
@@ -122,3 +118,69 @@ func (s *ListenerTestSuite) TestSoftStop() {
 
 	s.Require().True(executeStarted2)
 }
+
+func (s *ListenerTestSuite) TestDone() {
+	listener := NewListener(1)
+
+	err := listener.StartCommand().Execute()
+	s.Require().NoError(err)
+
+	listener.GetQueue().Put(listener.SoftStopCommand())
+	<-listener.Done()
+
+	err = listener.HardStopCommand().Execute()
+	s.Require().NoError(err)
+}
+
+func (s *ListenerTestSuite) TestAfterExecute() {
+	listener := NewListener(3)
+
+	command1 := mock.CommandMock{}
+	command1.On("Execute").Return(nil)
+	command2 := mock.CommandMock{}
+	command2.On("Execute").Return(errSomeError)
+
+	var handled, executed []error
+	listener.SetErrorHandler(func(command core.Command, err error) {
+		handled = append(handled, err)
+	})
+	listener.SetAfterExecute(func(command core.Command, err error) {
+		executed = append(executed, err)
+	})
+
+	listener.GetQueue().Put(&command1)
+	listener.GetQueue().Put(&command2)
+	listener.GetQueue().Put(listener.SoftStopCommand())
+
+	err := listener.StartCommand().Execute()
+	s.Require().NoError(err)
+	<-listener.Done()
+
+	s.Require().Equal([]error{errSomeError}, handled)
+	s.Require().Equal([]error{nil, errSomeError, nil}, executed)
+}
+
+func (s *ListenerTestSuite) TestPutWhileStopping() {
+	listener := NewListener(1)
+
+	command := mock.CommandMock{}
+	command.On("Execute").Return(nil)
+	listener.GetQueue().Put(&command)
+
+	putChan := make(chan struct{})
+	go func() {
+		listener.GetQueue().Put(&command)
+		close(putChan)
+	}()
+
+	err := listener.SoftStopCommand().Execute()
+	s.Require().NoError(err)
+	<-putChan
+
+	listener.GetQueue().Put(&command)
+
+	err = listener.StartCommand().Execute()
+	s.Require().NoError(err)
+	<-listener.Done()
+	command.AssertNumberOfCalls(s.T(), "Execute", 1)
+}
@@ -1,8 +1,13 @@
 package queue
 
-import "modules/internal/core"
+import (
+	"sync"
+
+	"modules/internal/core"
+)
 
 type Queue struct {
+	mutex        sync.RWMutex
 	commandsChan chan core.Command
 	aliveChan    chan int
 }
@@ -12,12 +17,48 @@ func (q *Queue) Get() (core.Command, bool) {
 	return command, ok
 }
 
+// Put drops the command once the listener is stopping, including a Put
+// blocked on a full queue when the stop happens.
 func (q *Queue) Put(command core.Command) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
 	select {
 	case <-q.aliveChan:
 		return
 	default:
 	}
 
-	q.commandsChan <- command
+	select {
+	case q.commandsChan <- command:
+	case <-q.aliveChan:
+	}
+}
+
+// TryPut puts the command unless the queue is full. Like Put it drops the
+// command once the listener is stopping, which counts as put.
+func (q *Queue) TryPut(command core.Command) bool {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	select {
+	case <-q.aliveChan:
+		return true
+	default:
+	}
+
+	select {
+	case q.commandsChan <- command:
+		return true
+	default:
+		return false
+	}
+}
+
+// close must follow closing aliveChan, which releases blocked Put calls.
+func (q *Queue) close() {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	close(q.commandsChan)
 }
@@ -0,0 +1,236 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+	bolterrors "go.etcd.io/bbolt/errors"
+
+	"modules/internal/object"
+)
+
+var (
+	gamesBucket   = []byte("games")
+	playersBucket = []byte("players")
+)
+
+// Bolt keeps the games in a bbolt file: the games bucket holds a bucket of
+// encoded objects per game, the players bucket the encoded players of every
+// game. Transactions are bbolt transactions, so an
+// Update is written atomically and durably.
+type Bolt struct {
+	db *bolt.DB
+}
+
+// OpenBolt opens the file at the path, creating it if needed. Only one
+// process can open the file at a time, opening it from another one fails
+// after a second.
+func OpenBolt(path string) (*Bolt, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(gamesBucket)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.CreateBucketIfNotExists(playersBucket)
+		return err
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	return &Bolt{db: db}, nil
+}
+
+func (b *Bolt) View(fn func(tx Tx) error) error {
+	return b.db.View(func(tx *bolt.Tx) error {
+		return fn(&boltTx{tx: tx})
+	})
+}
+
+func (b *Bolt) Update(fn func(tx Tx) error) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return fn(&boltTx{tx: tx})
+	})
+}
+
+func (b *Bolt) Close() error {
+	return b.db.Close()
+}
+
+type boltTx struct {
+	tx *bolt.Tx
+}
+
+func (tx *boltTx) games() *bolt.Bucket {
+	return tx.tx.Bucket(gamesBucket)
+}
+
+func (tx *boltTx) players() *bolt.Bucket {
+	return tx.tx.Bucket(playersBucket)
+}
+
+func (tx *boltTx) game(id string) (*bolt.Bucket, error) {
+	result := tx.games().Bucket([]byte(id))
+	if result == nil {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownGame, id)
+	}
+
+	return result, nil
+}
+
+func (tx *boltTx) Games() ([]string, error) {
+	var result []string
+	err := tx.games().ForEachBucket(func(id []byte) error {
+		result = append(result, string(id))
+		return nil
+	})
+
+	return result, err
+}
+
+func (tx *boltTx) SaveGame(id string, objects *object.Registry) error {
+	if !tx.tx.Writable() {
+		return ErrReadOnly
+	}
+
+	encoded, err := encodeObjects(objects)
+	if err != nil {
+		return err
+	}
+
+	players, err := encodePlayers(objects)
+	if err != nil {
+		return err
+	}
+
+	err = tx.players().Put([]byte(id), players)
+	if err != nil {
+		return err
+	}
+
+	err = tx.games().DeleteBucket([]byte(id))
+	if err != nil && !errors.Is(err, bolterrors.ErrBucketNotFound) {
+		return err
+	}
+
+	game, err := tx.games().CreateBucket([]byte(id))
+	if err != nil {
+		return err
+	}
+
+	for objectID, data := range encoded {
+		err = game.Put([]byte(objectID), data)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (tx *boltTx) LoadGame(id string) (*object.Registry, error) {
+	game, err := tx.game(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// bbolt owns the values only during the transaction, so they are
+	// decoded before it ends.
+	result := object.NewRegistry()
+	err = game.ForEach(func(objectID, data []byte) error {
+		o, err := decodeObject(data)
+		if err != nil {
+			return fmt.Errorf("object %q: %w", objectID, err)
+		}
+
+		return result.Add(string(objectID), o)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Games created by SaveObject have no players.
+	if data := tx.players().Get([]byte(id)); data != nil {
+		players, err := decodePlayers(data)
+		if err != nil {
+			return nil, err
+		}
+		result.AddPlayer(players...)
+	}
+
+	return result, nil
+}
+
+func (tx *boltTx) DeleteGame(id string) error {
+	if !tx.tx.Writable() {
+		return ErrReadOnly
+	}
+
+	err := tx.games().DeleteBucket([]byte(id))
+	if errors.Is(err, bolterrors.ErrBucketNotFound) {
+		return fmt.Errorf("%w: %q", ErrUnknownGame, id)
+	}
+	if err != nil {
+		return err
+	}
+
+	return tx.players().Delete([]byte(id))
+}
+
+func (tx *boltTx) SaveObject(gameID, objectID string, o *object.Object) error {
+	if !tx.tx.Writable() {
+		return ErrReadOnly
+	}
+
+	data, err := encodeObject(o)
+	if err != nil {
+		return fmt.Errorf("object %q: %w", objectID, err)
+	}
+
+	game, err := tx.games().CreateBucketIfNotExists([]byte(gameID))
+	if err != nil {
+		return err
+	}
+
+	return game.Put([]byte(objectID), data)
+}
+
+func (tx *boltTx) LoadObject(gameID, objectID string) (*object.Object, error) {
+	game, err := tx.game(gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	data := game.Get([]byte(objectID))
+	if data == nil {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownObject, objectID)
+	}
+
+	return decodeObject(data)
+}
+
+func (tx *boltTx) DeleteObject(gameID, objectID string) error {
+	if !tx.tx.Writable() {
+		return ErrReadOnly
+	}
+
+	game, err := tx.game(gameID)
+	if err != nil {
+		return err
+	}
+
+	if game.Get([]byte(objectID)) == nil {
+		return fmt.Errorf("%w: %q", ErrUnknownObject, objectID)
+	}
+
+	return game.Delete([]byte(objectID))
+}
@@ -0,0 +1,13 @@
+package repository
+
+import "fmt"
+
+var (
+	ErrUnknownGame = fmt.Errorf("unknown game")
+
+	ErrUnknownObject = fmt.Errorf("unknown object")
+
+	ErrReadOnly = fmt.Errorf("read-only transaction")
+
+	ErrInvalidData = fmt.Errorf("invalid stored data")
+)
@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+
+	"modules/internal/object"
+)
+
+// Memory keeps the games in memory, e.g. for tests or games that need not
+// survive a restart. Objects are stored encoded like on disk, so the loaded
+// objects never share state with the saved ones.
+type Memory struct {
+	mutex   sync.RWMutex
+	games   map[string]map[string][]byte
+	players map[string][]string
+}
+
+func NewMemory() *Memory {
+	return &Memory{
+		games:   map[string]map[string][]byte{},
+		players: map[string][]string{},
+	}
+}
+
+func (m *Memory) View(fn func(tx Tx) error) error {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return fn(&memoryTx{games: m.games, players: m.players})
+}
+
+// Update runs fn on a copy of the games, which replaces them once fn
+// succeeds. The encoded objects and the players are never modified, so
+// copying the maps is enough.
+func (m *Memory) Update(fn func(tx Tx) error) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	tx := &memoryTx{
+		games:    make(map[string]map[string][]byte, len(m.games)),
+		players:  maps.Clone(m.players),
+		writable: true,
+	}
+	for id, objects := range m.games {
+		tx.games[id] = maps.Clone(objects)
+	}
+
+	err := fn(tx)
+	if err != nil {
+		return err
+	}
+
+	m.games = tx.games
+	m.players = tx.players
+	return nil
+}
+
+func (m *Memory) Close() error {
+	return nil
+}
+
+type memoryTx struct {
+	games    map[string]map[string][]byte
+	players  map[string][]string
+	writable bool
+}
+
+func (tx *memoryTx) Games() ([]string, error) {
+	return slices.Sorted(maps.Keys(tx.games)), nil
+}
+
+func (tx *memoryTx) SaveGame(id string, objects *object.Registry) error {
+	if !tx.writable {
+		return ErrReadOnly
+	}
+
+	encoded, err := encodeObjects(objects)
+	if err != nil {
+		return err
+	}
+
+	tx.games[id] = encoded
+	tx.players[id] = objects.Players()
+	return nil
+}
+
+func (tx *memoryTx) LoadGame(id string) (*object.Registry, error) {
+	objects, ok := tx.games[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownGame, id)
+	}
+
+	result, err := decodeObjects(objects)
+	if err != nil {
+		return nil, err
+	}
+	result.AddPlayer(tx.players[id]...)
+
+	return result, nil
+}
+
+func (tx *memoryTx) DeleteGame(id string) error {
+	if !tx.writable {
+		return ErrReadOnly
+	}
+
+	if _, ok := tx.games[id]; !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownGame, id)
+	}
+
+	delete(tx.games, id)
+	delete(tx.players, id)
+	return nil
+}
+
+func (tx *memoryTx) SaveObject(gameID, objectID string, o *object.Object) error {
+	if !tx.writable {
+		return ErrReadOnly
+	}
+
+	data, err := encodeObject(o)
+	if err != nil {
+		return fmt.Errorf("object %q: %w", objectID, err)
+	}
+
+	if _, ok := tx.games[gameID]; !ok {
+		tx.games[gameID] = map[string][]byte{}
+	}
+	tx.games[gameID][objectID] = data
+	return nil
+}
+
+func (tx *memoryTx) LoadObject(gameID, objectID string) (*object.Object, error) {
+	objects, ok := tx.games[gameID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownGame, gameID)
+	}
+
+	data, ok := objects[objectID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownObject, objectID)
+	}
+
+	return decodeObject(data)
+}
+
+func (tx *memoryTx) DeleteObject(gameID, objectID string) error {
+	if !tx.writable {
+		return ErrReadOnly
+	}
+
+	objects, ok := tx.games[gameID]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownGame, gameID)
+	}
+
+	if _, ok := objects[objectID]; !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownObject, objectID)
+	}
+
+	delete(objects, objectID)
+	return nil
+}
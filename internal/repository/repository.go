@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"modules/internal/object"
+)
+
+// Repository stores the objects and the players of games, but not the
+// commands scheduled for them. Everything written in one Update
+// is committed together or not at all.
+type Repository interface {
+	// View runs fn in a read-only transaction.
+	View(fn func(tx Tx) error) error
+	// Update runs fn in a read-write transaction that is committed if fn
+	// returns nil and rolled back otherwise.
+	Update(fn func(tx Tx) error) error
+	Close() error
+}
+
+// Tx reads and writes the stored games. It is valid only within the
+// function it is passed to.
+type Tx interface {
+	Games() ([]string, error)
+	// SaveGame replaces all the stored objects and players of the game.
+	SaveGame(id string, objects *object.Registry) error
+	LoadGame(id string) (*object.Registry, error)
+	DeleteGame(id string) error
+
+	// SaveObject stores the object of the game, creating the game if
+	// needed.
+	SaveObject(gameID, objectID string, o *object.Object) error
+	LoadObject(gameID, objectID string) (*object.Object, error)
+	DeleteObject(gameID, objectID string) error
+}
+
+// SaveCommand saves the objects of the game. Put into the game queue, it is
+// executed between the commands changing the objects, so the saved state is
+// consistent.
+type SaveCommand struct {
+	repository Repository
+	gameID     string
+	objects    *object.Registry
+}
+
+func NewSaveCommand(repository Repository, gameID string, objects *object.Registry) *SaveCommand {
+	return &SaveCommand{
+		repository: repository,
+		gameID:     gameID,
+		objects:    objects,
+	}
+}
+
+func (c *SaveCommand) Execute() error {
+	return c.repository.Update(func(tx Tx) error {
+		return tx.SaveGame(c.gameID, c.objects)
+	})
+}
+
+// encodeObjects encodes the properties of every object, the stores keep
+// them by object id.
+func encodeObjects(objects *object.Registry) (map[string][]byte, error) {
+	result := map[string][]byte{}
+	for _, id := range objects.IDs() {
+		o, err := objects.Get(id)
+		if err != nil {
+			continue
+		}
+
+		data, err := encodeObject(o)
+		if err != nil {
+			return nil, fmt.Errorf("object %q: %w", id, err)
+		}
+		result[id] = data
+	}
+
+	return result, nil
+}
+
+func encodeObject(o *object.Object) ([]byte, error) {
+	return json.Marshal(o.Properties())
+}
+
+func decodeObject(data []byte) (*object.Object, error) {
+	var properties map[string]interface{}
+	err := json.Unmarshal(data, &properties)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidData, err)
+	}
+
+	converted, err := object.ConvertProperties(properties)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidData, err)
+	}
+
+	return object.New(converted), nil
+}
+
+func encodePlayers(objects *object.Registry) ([]byte, error) {
+	return json.Marshal(objects.Players())
+}
+
+func decodePlayers(data []byte) ([]string, error) {
+	var result []string
+	err := json.Unmarshal(data, &result)
+	if err != nil {
+		return nil, fmt.Errorf("%w: players: %w", ErrInvalidData, err)
+	}
+
+	return result, nil
+}
+
+// decodeObjects is the reverse of encodeObjects.
+func decodeObjects(encoded map[string][]byte) (*object.Registry, error) {
+	result := object.NewRegistry()
+	for id, data := range encoded {
+		o, err := decodeObject(data)
+		if err != nil {
+			return nil, fmt.Errorf("object %q: %w", id, err)
+		}
+
+		err = result.Add(id, o)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"modules/internal/object"
+	"modules/internal/vector"
+)
+
+var errSomeError = fmt.Errorf("some error")
+
+func TestMemory(t *testing.T) {
+	suite.Run(t, &RepositoryTestSuite{open: func(s *RepositoryTestSuite) Repository {
+		return NewMemory()
+	}})
+}
+
+func TestBolt(t *testing.T) {
+	suite.Run(t, &RepositoryTestSuite{open: func(s *RepositoryTestSuite) Repository {
+		repository, err := OpenBolt(filepath.Join(s.T().TempDir(), "games.db"))
+		s.Require().NoError(err)
+		return repository
+	}})
+}
+
+type RepositoryTestSuite struct {
+	suite.Suite
+
+	open       func(s *RepositoryTestSuite) Repository
+	repository Repository
+	objects    *object.Registry
+	ship       *object.Object
+}
+
+func (s *RepositoryTestSuite) SetupTest() {
+	s.repository = s.open(s)
+	s.ship = object.New(map[string]interface{}{
+		object.Position: vector.New([]int{12, 5}),
+		object.Velocity: vector.New([]int{-7, 3}),
+		object.Fuel:     100,
+		object.Owner:    "alice",
+		object.ACL:      map[string]interface{}{"bob": []string{"move"}},
+	})
+	s.objects = object.NewRegistry()
+	s.Require().NoError(s.objects.Add("548", s.ship))
+	s.Require().NoError(s.objects.SetOwner("alice", "548"))
+	s.objects.AddPlayer("bob")
+}
+
+func (s *RepositoryTestSuite) TearDownTest() {
+	s.Require().NoError(s.repository.Close())
+}
+
+func (s *RepositoryTestSuite) save(gameID string) {
+	s.Require().NoError(NewSaveCommand(s.repository, gameID, s.objects).Execute())
+}
+
+func (s *RepositoryTestSuite) load(gameID string) (result *object.Registry, err error) {
+	err = s.repository.View(func(tx Tx) error {
+		result, err = tx.LoadGame(gameID)
+		return err
+	})
+	return
+}
+
+func (s *RepositoryTestSuite) TestSaveLoadGame() {
+	s.save("a")
+	s.save("b")
+
+	objects, err := s.load("a")
+	s.Require().NoError(err)
+	s.Require().Equal([]string{"548"}, objects.IDs())
+	s.Require().Equal([]string{"alice", "bob"}, objects.Players())
+	ship, err := objects.Get("548")
+	s.Require().NoError(err)
+	s.Require().Equal(s.ship.Properties(), ship.Properties())
+	s.Require().NotSame(s.ship, ship)
+
+	s.Require().NoError(s.repository.View(func(tx Tx) error {
+		games, err := tx.Games()
+		s.Require().Equal([]string{"a", "b"}, games)
+		return err
+	}))
+
+	_, err = s.load("c")
+	s.Require().ErrorIs(err, ErrUnknownGame)
+}
+
+func (s *RepositoryTestSuite) TestSaveGameReplaces() {
+	s.Require().NoError(s.objects.Add("549", object.New(map[string]interface{}{object.Fuel: 5})))
+	s.save("a")
+	s.Require().NoError(s.objects.Remove("549"))
+	s.save("a")
+
+	objects, err := s.load("a")
+	s.Require().NoError(err)
+	s.Require().Equal([]string{"548"}, objects.IDs())
+}
+
+func (s *RepositoryTestSuite) TestObjects() {
+	s.Require().NoError(s.repository.Update(func(tx Tx) error {
+		return tx.SaveObject("a", "548", s.ship)
+	}))
+
+	s.Require().NoError(s.repository.View(func(tx Tx) error {
+		ship, err := tx.LoadObject("a", "548")
+		s.Require().NoError(err)
+		s.Require().Equal(s.ship.Properties(), ship.Properties())
+
+		_, err = tx.LoadObject("a", "549")
+		s.Require().ErrorIs(err, ErrUnknownObject)
+		_, err = tx.LoadObject("b", "548")
+		s.Require().ErrorIs(err, ErrUnknownGame)
+		return nil
+	}))
+
+	s.Require().NoError(s.repository.Update(func(tx Tx) error {
+		s.Require().NoError(tx.DeleteObject("a", "548"))
+		s.Require().ErrorIs(tx.DeleteObject("a", "548"), ErrUnknownObject)
+		s.Require().NoError(tx.DeleteGame("a"))
+		s.Require().ErrorIs(tx.DeleteGame("a"), ErrUnknownGame)
+		return nil
+	}))
+}
+
+func (s *RepositoryTestSuite) TestRollback() {
+	s.save("a")
+
+	err := s.repository.Update(func(tx Tx) error {
+		s.Require().NoError(tx.SaveObject("a", "549", s.ship))
+		s.Require().NoError(tx.DeleteObject("a", "548"))
+		s.Require().NoError(tx.SaveGame("b", s.objects))
+		return errSomeError
+	})
+	s.Require().ErrorIs(err, errSomeError)
+
+	objects, err := s.load("a")
+	s.Require().NoError(err)
+	s.Require().Equal([]string{"548"}, objects.IDs())
+	_, err = s.load("b")
+	s.Require().ErrorIs(err, ErrUnknownGame)
+}
+
+func (s *RepositoryTestSuite) TestReadOnly() {
+	s.save("a")
+
+	s.Require().NoError(s.repository.View(func(tx Tx) error {
+		s.Require().ErrorIs(tx.SaveGame("b", s.objects), ErrReadOnly)
+		s.Require().ErrorIs(tx.SaveObject("a", "549", s.ship), ErrReadOnly)
+		s.Require().ErrorIs(tx.DeleteObject("a", "548"), ErrReadOnly)
+		s.Require().ErrorIs(tx.DeleteGame("a"), ErrReadOnly)
+		return nil
+	}))
+}
+
+func TestBoltReopen(t *testing.T) {
+	s := &RepositoryTestSuite{}
+	s.SetT(t)
+	path := filepath.Join(t.TempDir(), "games.db")
+	s.open = func(s *RepositoryTestSuite) Repository {
+		repository, err := OpenBolt(path)
+		s.Require().NoError(err)
+		return repository
+	}
+
+	s.SetupTest()
+	s.save("a")
+	s.TearDownTest()
+
+	s.SetupTest()
+	defer s.TearDownTest()
+	objects, err := s.load("a")
+	s.Require().NoError(err)
+	ship, err := objects.Get("548")
+	s.Require().NoError(err)
+	s.Require().Equal(s.ship.Properties(), ship.Properties())
+}
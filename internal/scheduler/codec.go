@@ -0,0 +1,64 @@
+package scheduler
+
+import (
+	"fmt"
+
+	"modules/internal/command"
+	"modules/internal/core"
+)
+
+// RegisterCodec makes the codec know StartMoveCommand, StopMoveCommand and
+// TickCommand, decoded into commands of the scheduler. A tick executes the
+// commands scheduled at the time it is executed, so the commands a decoded
+// tick executes are the ones scheduled by the decoded start commands before
+// it.
+func RegisterCodec(codec *command.Codec, scheduler *Scheduler) {
+	codec.Register("start_move", &StartMoveCommand{}, func(c *command.Codec, cmd core.Command) (command.DTO, error) {
+		start := cmd.(*StartMoveCommand)
+		id, err := c.ObjectID(start.object)
+		if err != nil {
+			return command.DTO{}, err
+		}
+
+		move, err := c.Encode(start.move)
+		if err != nil {
+			return command.DTO{}, err
+		}
+
+		return command.DTO{ObjectID: id, Commands: []command.DTO{move}}, nil
+	}, func(c *command.Codec, dto command.DTO) (core.Command, error) {
+		if len(dto.Commands) != 1 {
+			return nil, fmt.Errorf("%w: start_move needs one command, got %d", command.ErrInvalidEncoding, len(dto.Commands))
+		}
+
+		target, err := c.Object(dto)
+		if err != nil {
+			return nil, err
+		}
+
+		move, err := c.Decode(dto.Commands[0])
+		if err != nil {
+			return nil, err
+		}
+
+		return NewStartMoveCommand(scheduler, target.Object(), move), nil
+	})
+
+	codec.Register("stop_move", &StopMoveCommand{}, func(c *command.Codec, cmd core.Command) (command.DTO, error) {
+		id, err := c.ObjectID(cmd.(*StopMoveCommand).object)
+		return command.DTO{ObjectID: id}, err
+	}, func(c *command.Codec, dto command.DTO) (core.Command, error) {
+		target, err := c.Object(dto)
+		if err != nil {
+			return nil, err
+		}
+
+		return NewStopMoveCommand(scheduler, target.Object()), nil
+	})
+
+	codec.Register("tick", &TickCommand{}, func(c *command.Codec, cmd core.Command) (command.DTO, error) {
+		return command.DTO{}, nil
+	}, func(c *command.Codec, dto command.DTO) (core.Command, error) {
+		return &TickCommand{scheduler: scheduler}, nil
+	})
+}
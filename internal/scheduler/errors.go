@@ -0,0 +1,7 @@
+package scheduler
+
+import "fmt"
+
+var (
+	ErrNotMoving = fmt.Errorf("object is not moving")
+)
@@ -0,0 +1,50 @@
+package scheduler
+
+import "modules/internal/core"
+
+type moveKey struct {
+	object interface{}
+}
+
+// StartMoveCommand makes the object move on every tick until StopMoveCommand
+// is executed for the same object.
+type StartMoveCommand struct {
+	scheduler *Scheduler
+	object    interface{}
+	move      core.Command
+}
+
+// NewStartMoveCommand schedules the move command, e.g. a MoveCommand. The
+// object identifies the movement, e.g. the game object the command moves.
+func NewStartMoveCommand(scheduler *Scheduler, object interface{}, move core.Command) *StartMoveCommand {
+	return &StartMoveCommand{
+		scheduler: scheduler,
+		object:    object,
+		move:      move,
+	}
+}
+
+func (c *StartMoveCommand) Execute() error {
+	c.scheduler.Add(moveKey{object: c.object}, c.move)
+	return nil
+}
+
+type StopMoveCommand struct {
+	scheduler *Scheduler
+	object    interface{}
+}
+
+func NewStopMoveCommand(scheduler *Scheduler, object interface{}) *StopMoveCommand {
+	return &StopMoveCommand{
+		scheduler: scheduler,
+		object:    object,
+	}
+}
+
+func (c *StopMoveCommand) Execute() error {
+	if !c.scheduler.Remove(moveKey{object: c.object}) {
+		return ErrNotMoving
+	}
+
+	return nil
+}
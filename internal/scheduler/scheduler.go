@@ -0,0 +1,189 @@
+package scheduler
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"modules/internal/clock"
+	"modules/internal/core"
+)
+
+const DefaultInterval = 100 * time.Millisecond
+
+type entry struct {
+	key     interface{}
+	command core.Command
+}
+
+// Scheduler executes registered commands once per tick. Ticks are put into
+// the game queue as commands, so scheduled commands run in the listener
+// goroutine like any other command of the game.
+type Scheduler struct {
+	queue        core.Queue
+	clock        clock.Clock
+	interval     time.Duration
+	errorHandler core.ErrorHandler
+
+	mutex   sync.Mutex
+	entries []entry
+	pending bool
+
+	stopChan chan struct{}
+	stopOnce sync.Once
+}
+
+func NewScheduler(queue core.Queue, clock clock.Clock, interval time.Duration) *Scheduler {
+	return &Scheduler{
+		queue:    queue,
+		clock:    clock,
+		interval: interval,
+		stopChan: make(chan struct{}),
+	}
+}
+
+// SetErrorHandler makes ticks hand every failed command to the handler
+// instead of returning the errors. The tick itself then never fails, so an
+// error handler repeating failed commands repeats only the failed ones.
+func (s *Scheduler) SetErrorHandler(errorHandler core.ErrorHandler) {
+	s.errorHandler = errorHandler
+}
+
+// Add schedules the command under the key replacing the command scheduled
+// under it before. Commands are executed in the order they were added.
+func (s *Scheduler) Add(key interface{}, command core.Command) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for i := range s.entries {
+		if s.entries[i].key == key {
+			s.entries[i].command = command
+			return
+		}
+	}
+
+	s.entries = append(s.entries, entry{key: key, command: command})
+}
+
+func (s *Scheduler) Remove(key interface{}) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for i := range s.entries {
+		if s.entries[i].key == key {
+			s.entries = append(s.entries[:i], s.entries[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *Scheduler) Has(key interface{}) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, e := range s.entries {
+		if e.key == key {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *Scheduler) Len() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return len(s.entries)
+}
+
+// Start runs the ticker until Stop is called or done is closed, which should
+// be the Done channel of the listener reading the queue. A tick is only put
+// into the queue when something is scheduled and the previous tick has been
+// executed, so a slow game skips ticks instead of piling them up.
+func (s *Scheduler) Start(done <-chan struct{}) {
+	go func() {
+		ticker := s.clock.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C():
+				if s.startTick() {
+					s.queue.Put(&TickCommand{scheduler: s})
+				}
+			case <-done:
+				return
+			case <-s.stopChan:
+				return
+			}
+		}
+	}()
+}
+
+func (s *Scheduler) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stopChan)
+	})
+}
+
+func (s *Scheduler) startTick() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.pending || len(s.entries) == 0 {
+		return false
+	}
+
+	s.pending = true
+	return true
+}
+
+// TickCommand executes every scheduled command. A command failing is removed
+// from the scheduler and handed to its error handler, if any. Otherwise its
+// error is returned joined with the errors of the other failed commands.
+type TickCommand struct {
+	scheduler *Scheduler
+}
+
+func (c *TickCommand) Execute() error {
+	s := c.scheduler
+
+	s.mutex.Lock()
+	s.pending = false
+	entries := make([]entry, len(s.entries))
+	copy(entries, s.entries)
+	s.mutex.Unlock()
+
+	var errs []error
+	for _, e := range entries {
+		err := e.command.Execute()
+		if err == nil {
+			continue
+		}
+
+		s.removeCommand(e.key, e.command)
+		if s.errorHandler != nil {
+			s.errorHandler(e.command, err)
+		} else {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// removeCommand removes the entry unless the command has been replaced.
+func (s *Scheduler) removeCommand(key interface{}, command core.Command) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for i := range s.entries {
+		if s.entries[i].key == key && s.entries[i].command == command {
+			s.entries = append(s.entries[:i], s.entries[i+1:]...)
+			return
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package scheduler
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"modules/internal/clock"
+	"modules/internal/command"
+	"modules/internal/core"
+	"modules/internal/mock"
+	"modules/internal/queue"
+	"modules/internal/vector"
+)
+
+var errSomeError = fmt.Errorf("some error")
+
+func TestScheduler(t *testing.T) {
+	suite.Run(t, new(SchedulerTestSuite))
+}
+
+type SchedulerTestSuite struct {
+	suite.Suite
+
+	queue     mock.QueueMock
+	clock     *clock.Fake
+	scheduler *Scheduler
+}
+
+func (s *SchedulerTestSuite) SetupTest() {
+	s.queue = mock.QueueMock{}
+	s.clock = clock.NewFake(time.Unix(0, 0))
+	s.scheduler = NewScheduler(&s.queue, s.clock, time.Millisecond)
+}
+
+func (s *SchedulerTestSuite) TestTick() {
+	var executed []string
+	command := func(name string, err error) core.Command {
+		result := &mock.CommandMock{}
+		result.On("Execute").Return(func() error {
+			executed = append(executed, name)
+			return err
+		})
+		return result
+	}
+
+	s.scheduler.Add("a", command("a", nil))
+	s.scheduler.Add("b", command("b", errSomeError))
+	s.scheduler.Add("c", command("c", nil))
+	s.scheduler.Add("a", command("a2", nil))
+	s.Require().Equal(3, s.scheduler.Len())
+
+	err := (&TickCommand{scheduler: s.scheduler}).Execute()
+	s.Require().ErrorIs(err, errSomeError)
+	s.Require().Equal([]string{"a2", "b", "c"}, executed)
+	s.Require().False(s.scheduler.Has("b"))
+
+	s.Require().True(s.scheduler.Remove("a"))
+	s.Require().False(s.scheduler.Remove("a"))
+
+	executed = nil
+	s.Require().NoError((&TickCommand{scheduler: s.scheduler}).Execute())
+	s.Require().Equal([]string{"c"}, executed)
+}
+
+func (s *SchedulerTestSuite) TestErrorHandler() {
+	var handled []core.Command
+	s.scheduler.SetErrorHandler(func(command core.Command, err error) {
+		s.Require().ErrorIs(err, errSomeError)
+		handled = append(handled, command)
+	})
+
+	executed := 0
+	a := &mock.CommandMock{}
+	a.On("Execute").Return(func() error {
+		executed++
+		return nil
+	})
+	b := &mock.CommandMock{}
+	b.On("Execute").Return(errSomeError)
+	s.scheduler.Add("a", a)
+	s.scheduler.Add("b", b)
+
+	s.Require().NoError((&TickCommand{scheduler: s.scheduler}).Execute())
+	s.Require().Equal([]core.Command{b}, handled)
+	s.Require().Equal(1, executed)
+	s.Require().True(s.scheduler.Has("a"))
+	s.Require().False(s.scheduler.Has("b"))
+}
+
+func (s *SchedulerTestSuite) TestSkipTicks() {
+	s.Require().False(s.scheduler.startTick())
+
+	s.scheduler.Add("a", &mock.CommandMock{})
+	s.Require().True(s.scheduler.startTick())
+	s.Require().False(s.scheduler.startTick())
+
+	s.scheduler.Remove("a")
+	s.Require().NoError((&TickCommand{scheduler: s.scheduler}).Execute())
+	s.scheduler.Add("a", &mock.CommandMock{})
+	s.Require().True(s.scheduler.startTick())
+}
+
+func (s *SchedulerTestSuite) TestStart() {
+	listener := queue.NewListener(10)
+	s.scheduler = NewScheduler(listener.GetQueue(), s.clock, time.Second)
+
+	ticks := make(chan struct{})
+	command := mock.CommandMock{}
+	command.On("Execute").Return(func() error {
+		ticks <- struct{}{}
+		return nil
+	})
+	s.scheduler.Add("a", &command)
+
+	s.Require().NoError(listener.StartCommand().Execute())
+	s.scheduler.Start(listener.Done())
+	s.clock.BlockUntil(1)
+	for i := 0; i < 3; i++ {
+		s.clock.Advance(time.Second)
+		<-ticks
+	}
+
+	s.scheduler.Stop()
+	s.Require().Eventually(func() bool {
+		return s.clock.Timers() == 0
+	}, time.Second, time.Millisecond)
+
+	listener.GetQueue().Put(listener.SoftStopCommand())
+	<-listener.Done()
+}
+
+func (s *SchedulerTestSuite) TestMove() {
+	movable := mock.MovableMock{}
+	movable.On("GetPosition").Return(vector.New([]int{12, 5}), nil).
+		On("GetVelocity").Return(vector.New([]int{-7, 3}), nil).
+		On("SetPosition", vector.New([]int{5, 8})).Return(nil)
+
+	ship := struct{ name string }{"548"}
+	s.Require().NoError(NewStartMoveCommand(s.scheduler, ship, command.NewMoveCommand(&movable)).Execute())
+	s.Require().True(s.scheduler.Has(moveKey{object: ship}))
+
+	s.Require().NoError((&TickCommand{scheduler: s.scheduler}).Execute())
+	movable.AssertNumberOfCalls(s.T(), "SetPosition", 1)
+
+	s.Require().NoError(NewStopMoveCommand(s.scheduler, ship).Execute())
+	s.Require().ErrorIs(NewStopMoveCommand(s.scheduler, ship).Execute(), ErrNotMoving)
+	s.Require().Zero(s.scheduler.Len())
+}
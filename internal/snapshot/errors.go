@@ -0,0 +1,9 @@
+package snapshot
+
+import "fmt"
+
+var (
+	ErrInvalidSnapshot = fmt.Errorf("invalid snapshot")
+
+	ErrUnsupportedVersion = fmt.Errorf("unsupported snapshot version")
+)
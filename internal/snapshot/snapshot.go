@@ -0,0 +1,158 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"modules/internal/clock"
+	"modules/internal/object"
+)
+
+// Version is written into every snapshot. Reading a snapshot of another
+// version fails, so that a changed format is never misread.
+const Version = 1
+
+const DefaultInterval = time.Minute
+
+// Snapshot is the state of the objects of a game with all their properties
+// and the players of the game. The commands scheduled for the game, e.g.
+// moves started by players or moves of projectiles, are not recorded.
+type Snapshot struct {
+	Version int                               `json:"version"`
+	GameID  string                            `json:"game_id"`
+	Taken   time.Time                         `json:"taken"`
+	Players []string                          `json:"players,omitempty"`
+	Objects map[string]map[string]interface{} `json:"objects"`
+}
+
+// Take copies the properties of the objects. It is consistent only if no
+// command changes the objects meanwhile, see TakeCommand.
+func Take(gameID string, objects *object.Registry, now time.Time) Snapshot {
+	result := Snapshot{
+		Version: Version,
+		GameID:  gameID,
+		Taken:   now,
+		Players: objects.Players(),
+		Objects: map[string]map[string]interface{}{},
+	}
+
+	for _, id := range objects.IDs() {
+		o, err := objects.Get(id)
+		if err == nil {
+			result.Objects[id] = o.Properties()
+		}
+	}
+
+	return result
+}
+
+// Restore recreates the objects and the players of the snapshot.
+func (s Snapshot) Restore() (*object.Registry, error) {
+	result := object.NewRegistry()
+	result.AddPlayer(s.Players...)
+	for id, properties := range s.Objects {
+		converted, err := object.ConvertProperties(properties)
+		if err != nil {
+			return nil, fmt.Errorf("object %q: %w", id, err)
+		}
+
+		err = result.Add(id, object.New(converted))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+func Write(w io.Writer, s Snapshot) error {
+	return json.NewEncoder(w).Encode(s)
+}
+
+func Read(r io.Reader) (Snapshot, error) {
+	var header struct {
+		Version int `json:"version"`
+	}
+	var result Snapshot
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return result, err
+	}
+
+	err = json.Unmarshal(data, &header)
+	if err != nil {
+		return result, fmt.Errorf("%w: %w", ErrInvalidSnapshot, err)
+	}
+
+	if header.Version != Version {
+		return result, fmt.Errorf("%w: %d", ErrUnsupportedVersion, header.Version)
+	}
+
+	err = json.Unmarshal(data, &result)
+	if err != nil {
+		return result, fmt.Errorf("%w: %w", ErrInvalidSnapshot, err)
+	}
+
+	return result, nil
+}
+
+// Save writes the snapshot to a temporary file that then replaces the file
+// at the path, so a crash while saving leaves the previous snapshot intact.
+func Save(path string, s Snapshot) error {
+	file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	err = Write(file, s)
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), path)
+}
+
+func Load(path string) (Snapshot, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	defer file.Close()
+
+	return Read(file)
+}
+
+// TakeCommand saves a snapshot of the game objects. Put into the game queue,
+// it is executed between the commands changing the objects, so the snapshot
+// is consistent.
+type TakeCommand struct {
+	gameID  string
+	objects *object.Registry
+	clock   clock.Clock
+	path    string
+}
+
+func NewTakeCommand(gameID string, objects *object.Registry, clock clock.Clock, path string) *TakeCommand {
+	return &TakeCommand{
+		gameID:  gameID,
+		objects: objects,
+		clock:   clock,
+		path:    path,
+	}
+}
+
+func (c *TakeCommand) Execute() error {
+	return Save(c.path, Take(c.gameID, c.objects, c.clock.Now()))
+}
@@ -0,0 +1,93 @@
+package snapshot
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"modules/internal/clock"
+	"modules/internal/object"
+	"modules/internal/vector"
+)
+
+func TestSnapshot(t *testing.T) {
+	suite.Run(t, new(SnapshotTestSuite))
+}
+
+type SnapshotTestSuite struct {
+	suite.Suite
+
+	objects *object.Registry
+	ship    *object.Object
+	path    string
+}
+
+func (s *SnapshotTestSuite) SetupTest() {
+	s.objects = object.NewRegistry()
+	s.ship = object.New(map[string]interface{}{
+		object.Position: vector.New([]int{12, 5}),
+		object.Velocity: vector.New([]int{-7, 3}),
+		object.Fuel:     100,
+		object.Owner:    "alice",
+	})
+	s.Require().NoError(s.objects.Add("548", s.ship))
+	s.Require().NoError(s.objects.SetOwner("alice", "548"))
+	s.objects.AddPlayer("bob")
+	s.path = filepath.Join(s.T().TempDir(), "game.json")
+}
+
+func (s *SnapshotTestSuite) TestSaveLoad() {
+	taken := time.Unix(1000, 0).UTC()
+	s.Require().NoError(Save(s.path, Take("game", s.objects, taken)))
+
+	loaded, err := Load(s.path)
+	s.Require().NoError(err)
+	s.Require().Equal("game", loaded.GameID)
+	s.Require().True(taken.Equal(loaded.Taken))
+
+	objects, err := loaded.Restore()
+	s.Require().NoError(err)
+	s.Require().Equal([]string{"548"}, objects.IDs())
+	s.Require().Equal([]string{"alice", "bob"}, objects.Players())
+	ship, err := objects.Get("548")
+	s.Require().NoError(err)
+	s.Require().Equal(s.ship.Properties(), ship.Properties())
+}
+
+func (s *SnapshotTestSuite) TestOverwrite() {
+	s.Require().NoError(Save(s.path, Take("game", s.objects, time.Unix(0, 0))))
+	s.Require().NoError(s.ship.SetProperty(object.Fuel, 30))
+	s.Require().NoError(Save(s.path, Take("game", s.objects, time.Unix(1, 0))))
+
+	loaded, err := Load(s.path)
+	s.Require().NoError(err)
+	s.Require().EqualValues(30, loaded.Objects["548"][object.Fuel])
+
+	files, err := filepath.Glob(filepath.Join(filepath.Dir(s.path), "*"))
+	s.Require().NoError(err)
+	s.Require().Equal([]string{s.path}, files)
+}
+
+func (s *SnapshotTestSuite) TestRead() {
+	_, err := Read(strings.NewReader(`{"version":2,"objects":{}}`))
+	s.Require().ErrorIs(err, ErrUnsupportedVersion)
+
+	_, err = Read(strings.NewReader(`{"version":`))
+	s.Require().ErrorIs(err, ErrInvalidSnapshot)
+
+	_, err = Read(strings.NewReader(`{"version":1,"objects":[]}`))
+	s.Require().ErrorIs(err, ErrInvalidSnapshot)
+}
+
+func (s *SnapshotTestSuite) TestTakeCommand() {
+	c := clock.NewFake(time.Unix(42, 0))
+	s.Require().NoError(NewTakeCommand("game", s.objects, c, s.path).Execute())
+
+	loaded, err := Load(s.path)
+	s.Require().NoError(err)
+	s.Require().True(c.Now().Equal(loaded.Taken))
+	s.Require().Contains(loaded.Objects, "548")
+}
@@ -0,0 +1,7 @@
+package spectator
+
+import "fmt"
+
+var (
+	ErrHubClosed = fmt.Errorf("spectator hub is closed")
+)
@@ -0,0 +1,370 @@
+package spectator
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"modules/internal/game"
+)
+
+const writeTimeout = 10 * time.Second
+
+// Frame is the JSON message sent to spectators. Objects holds the properties
+// changed since the previous frame, a nil property value means the property
+// was removed.
+type Frame struct {
+	GameID  string                            `json:"game"`
+	Objects map[string]map[string]interface{} `json:"objects,omitempty"`
+	Removed []string                          `json:"removed,omitempty"`
+}
+
+// Hub broadcasts state changes of games to WebSocket spectators. All
+// spectators of a game share one subscription to the game events, which are
+// published by the game listener after every command.
+type Hub struct {
+	manager  *game.Manager
+	upgrader websocket.Upgrader
+
+	mutex  sync.Mutex
+	rooms  map[string]*room
+	closed bool
+}
+
+func NewHub(manager *game.Manager) *Hub {
+	return &Hub{
+		manager: manager,
+		rooms:   map[string]*room{},
+	}
+}
+
+// ServeHTTP upgrades GET /{game_id} to a WebSocket streaming frames of the
+// game. The first frame holds the whole game state.
+func (h *Hub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	gameID := strings.Trim(r.URL.Path, "/")
+	if gameID == "" || strings.Contains(gameID, "/") {
+		http.NotFound(w, r)
+		return
+	}
+
+	g, err := h.manager.Get(gameID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// the upgrader has replied with an error
+		return
+	}
+
+	c := newClient(conn, gameID)
+	err = h.join(g, c)
+	if err != nil {
+		c.closeWith(websocket.CloseGoingAway, err.Error())
+		return
+	}
+
+	go c.write()
+	c.read()
+	h.leave(gameID, c)
+}
+
+// Close disconnects all spectators.
+func (h *Hub) Close() {
+	h.mutex.Lock()
+	rooms := h.rooms
+	h.rooms = map[string]*room{}
+	h.closed = true
+	h.mutex.Unlock()
+
+	for _, r := range rooms {
+		r.close(websocket.CloseGoingAway, "server is shutting down")
+	}
+}
+
+func (h *Hub) join(g *game.Game, c *client) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if h.closed {
+		return ErrHubClosed
+	}
+
+	r, ok := h.rooms[g.ID()]
+	if !ok {
+		r = newRoom(g)
+		h.rooms[g.ID()] = r
+		go h.run(r)
+	}
+
+	c.snapshot(g)
+	r.add(c)
+	return nil
+}
+
+func (h *Hub) leave(gameID string, c *client) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	r, ok := h.rooms[gameID]
+	if !ok {
+		return
+	}
+
+	if r.remove(c) == 0 {
+		delete(h.rooms, gameID)
+		r.cancel()
+	}
+}
+
+func (h *Hub) run(r *room) {
+	for {
+		select {
+		case event, ok := <-r.events:
+			if !ok {
+				// the room fell behind the game, or the last client left
+				h.mutex.Lock()
+				if h.rooms[r.game.ID()] == r {
+					delete(h.rooms, r.game.ID())
+				}
+				h.mutex.Unlock()
+
+				r.close(websocket.CloseTryAgainLater, "fell behind the game")
+				return
+			}
+			r.broadcast(event)
+		case <-r.game.Done():
+			// the events published while the game was stopping
+			for len(r.events) != 0 {
+				r.broadcast(<-r.events)
+			}
+
+			h.mutex.Lock()
+			if h.rooms[r.game.ID()] == r {
+				delete(h.rooms, r.game.ID())
+			}
+			h.mutex.Unlock()
+
+			r.close(websocket.CloseNormalClosure, "game is over")
+			return
+		}
+	}
+}
+
+type room struct {
+	game   *game.Game
+	events <-chan game.Event
+	cancel func()
+
+	mutex   sync.Mutex
+	clients map[*client]struct{}
+}
+
+func newRoom(g *game.Game) *room {
+	events, cancel := g.Subscribe()
+	// the initial state sent by Subscribe is replaced by client snapshots
+	for len(events) != 0 {
+		<-events
+	}
+
+	return &room{
+		game:    g,
+		events:  events,
+		cancel:  cancel,
+		clients: map[*client]struct{}{},
+	}
+}
+
+func (r *room) add(c *client) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.clients[c] = struct{}{}
+}
+
+func (r *room) remove(c *client) int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	delete(r.clients, c)
+	return len(r.clients)
+}
+
+func (r *room) broadcast(event game.Event) {
+	changed, ok := event.(game.StateChanged)
+	if !ok {
+		return
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for c := range r.clients {
+		c.push(changed)
+	}
+}
+
+func (r *room) close(code int, text string) {
+	r.cancel()
+
+	r.mutex.Lock()
+	clients := r.clients
+	r.clients = map[*client]struct{}{}
+	r.mutex.Unlock()
+
+	for c := range clients {
+		c.closeWith(code, text)
+	}
+}
+
+// client coalesces the changes not yet written to the connection, so a slow
+// spectator gets fewer frames with the latest state instead of stalling the
+// room or growing a backlog.
+type client struct {
+	conn   *websocket.Conn
+	gameID string
+
+	mutex   sync.Mutex
+	objects map[string]map[string]interface{}
+	removed map[string]struct{}
+
+	writeMutex sync.Mutex
+	notify     chan struct{}
+	done       chan struct{}
+	closeOnce  sync.Once
+}
+
+func newClient(conn *websocket.Conn, gameID string) *client {
+	return &client{
+		conn:    conn,
+		gameID:  gameID,
+		objects: map[string]map[string]interface{}{},
+		removed: map[string]struct{}{},
+		notify:  make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+}
+
+func (c *client) snapshot(g *game.Game) {
+	for _, id := range g.Objects().IDs() {
+		o, err := g.Objects().Get(id)
+		if err != nil {
+			continue
+		}
+		c.push(game.StateChanged{ObjectID: id, Properties: o.Properties()})
+	}
+}
+
+func (c *client) push(changed game.StateChanged) {
+	c.mutex.Lock()
+	if changed.Removed {
+		delete(c.objects, changed.ObjectID)
+		c.removed[changed.ObjectID] = struct{}{}
+	} else {
+		delete(c.removed, changed.ObjectID)
+		properties, ok := c.objects[changed.ObjectID]
+		if !ok {
+			properties = make(map[string]interface{}, len(changed.Properties))
+			c.objects[changed.ObjectID] = properties
+		}
+		for key, value := range changed.Properties {
+			properties[key] = value
+		}
+	}
+	c.mutex.Unlock()
+
+	select {
+	case c.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (c *client) take() Frame {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	result := Frame{GameID: c.gameID}
+	if len(c.objects) != 0 {
+		result.Objects = c.objects
+		c.objects = map[string]map[string]interface{}{}
+	}
+
+	for id := range c.removed {
+		result.Removed = append(result.Removed, id)
+	}
+	sort.Strings(result.Removed)
+	c.removed = map[string]struct{}{}
+
+	return result
+}
+
+func (c *client) write() {
+	for {
+		select {
+		case <-c.notify:
+			frame := c.take()
+			if frame.Objects == nil && frame.Removed == nil {
+				continue
+			}
+
+			err := c.writeFrame(frame)
+			if err != nil {
+				c.closeWith(websocket.CloseInternalServerErr, err.Error())
+				return
+			}
+		case <-c.done:
+			return
+		}
+	}
+}
+
+func (c *client) writeFrame(frame Frame) error {
+	data, err := json.Marshal(frame)
+	if err != nil {
+		return err
+	}
+
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+
+	err = c.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	if err != nil {
+		return err
+	}
+
+	return c.conn.WriteMessage(websocket.TextMessage, data)
+}
+
+// read discards messages of the spectator until the connection is closed.
+func (c *client) read() {
+	for {
+		_, _, err := c.conn.ReadMessage()
+		if err != nil {
+			c.closeWith(websocket.CloseNormalClosure, "")
+			return
+		}
+	}
+}
+
+// closeWith flushes the pending changes and closes the connection.
+func (c *client) closeWith(code int, text string) {
+	c.closeOnce.Do(func() {
+		close(c.done)
+
+		frame := c.take()
+		if frame.Objects != nil || frame.Removed != nil {
+			_ = c.writeFrame(frame)
+		}
+
+		message := websocket.FormatCloseMessage(code, text)
+		_ = c.conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(writeTimeout))
+		_ = c.conn.Close()
+	})
+}
@@ -0,0 +1,183 @@
+package spectator
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/suite"
+
+	"modules/internal/game"
+	"modules/internal/interpreter"
+	"modules/internal/object"
+	"modules/internal/vector"
+)
+
+var registerOperations sync.Once
+
+func TestHub(t *testing.T) {
+	suite.Run(t, new(HubTestSuite))
+}
+
+type HubTestSuite struct {
+	suite.Suite
+
+	manager *game.Manager
+	game    *game.Game
+	hub     *Hub
+	server  *httptest.Server
+}
+
+func (s *HubTestSuite) SetupTest() {
+	registerOperations.Do(func() {
+		s.Require().NoError(interpreter.RegisterOperations())
+	})
+
+	objects := object.NewRegistry()
+	s.Require().NoError(objects.Add("548", object.New(map[string]interface{}{
+		object.Position: vector.New([]int{12, 5}),
+		object.Velocity: vector.New([]int{-7, 3}),
+	})))
+
+	var err error
+	s.manager = game.NewManager(10)
+	s.game, err = s.manager.Create("spectator_game", objects)
+	s.Require().NoError(err)
+
+	s.hub = NewHub(s.manager)
+	s.server = httptest.NewServer(s.hub)
+}
+
+func (s *HubTestSuite) TearDownTest() {
+	s.hub.Close()
+	s.server.Close()
+	_ = s.manager.Shutdown(context.Background())
+}
+
+func (s *HubTestSuite) dial(gameID string) *websocket.Conn {
+	url := "ws" + strings.TrimPrefix(s.server.URL, "http") + "/" + gameID
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	s.Require().NoError(err)
+	return conn
+}
+
+func (s *HubTestSuite) receive(conn *websocket.Conn) Frame {
+	s.Require().NoError(conn.SetReadDeadline(time.Now().Add(time.Second)))
+
+	var result Frame
+	s.Require().NoError(conn.ReadJSON(&result))
+	return result
+}
+
+func (s *HubTestSuite) move() {
+	err := s.manager.Execute(context.Background(), interpreter.Message{
+		GameID:      "spectator_game",
+		ObjectID:    "548",
+		OperationID: "move",
+	})
+	s.Require().NoError(err)
+}
+
+func (s *HubTestSuite) TestBroadcast() {
+	conn1 := s.dial("spectator_game")
+	defer conn1.Close()
+	conn2 := s.dial("spectator_game")
+	defer conn2.Close()
+
+	snapshot := Frame{
+		GameID: "spectator_game",
+		Objects: map[string]map[string]interface{}{
+			"548": {
+				"position": []interface{}{float64(12), float64(5)},
+				"velocity": []interface{}{float64(-7), float64(3)},
+			},
+		},
+	}
+	s.Require().Equal(snapshot, s.receive(conn1))
+	s.Require().Equal(snapshot, s.receive(conn2))
+
+	s.move()
+
+	moved := Frame{
+		GameID: "spectator_game",
+		Objects: map[string]map[string]interface{}{
+			"548": {"position": []interface{}{float64(5), float64(8)}},
+		},
+	}
+	s.Require().Equal(moved, s.receive(conn1))
+	s.Require().Equal(moved, s.receive(conn2))
+
+	s.Require().NoError(s.game.Objects().Remove("548"))
+	s.Require().NoError(s.manager.Stop("spectator_game", false))
+
+	s.Require().Equal(Frame{GameID: "spectator_game", Removed: []string{"548"}}, s.receive(conn1))
+	_, _, err := conn1.ReadMessage()
+	s.Require().True(websocket.IsCloseError(err, websocket.CloseNormalClosure), err)
+}
+
+func (s *HubTestSuite) TestCoalesce() {
+	c := newClient(nil, "spectator_game")
+	c.push(game.StateChanged{ObjectID: "548", Properties: map[string]interface{}{object.Position: vector.New([]int{1, 1})}})
+	c.push(game.StateChanged{ObjectID: "548", Properties: map[string]interface{}{object.Velocity: vector.New([]int{2, 2})}})
+	c.push(game.StateChanged{ObjectID: "548", Properties: map[string]interface{}{object.Position: vector.New([]int{3, 3})}})
+	c.push(game.StateChanged{ObjectID: "549", Properties: map[string]interface{}{object.Position: vector.New([]int{0, 0})}})
+	c.push(game.StateChanged{ObjectID: "549", Removed: true})
+
+	s.Require().Len(c.notify, 1)
+	s.Require().Equal(Frame{
+		GameID: "spectator_game",
+		Objects: map[string]map[string]interface{}{
+			"548": {
+				object.Position: vector.New([]int{3, 3}),
+				object.Velocity: vector.New([]int{2, 2}),
+			},
+		},
+		Removed: []string{"549"},
+	}, c.take())
+	s.Require().Equal(Frame{GameID: "spectator_game"}, c.take())
+}
+
+func (s *HubTestSuite) TestLeave() {
+	conn := s.dial("spectator_game")
+	s.receive(conn)
+	s.Require().NoError(conn.Close())
+
+	s.Require().Eventually(func() bool {
+		s.hub.mutex.Lock()
+		defer s.hub.mutex.Unlock()
+		return len(s.hub.rooms) == 0
+	}, time.Second, time.Millisecond)
+
+	conn = s.dial("spectator_game")
+	defer conn.Close()
+	s.receive(conn)
+	s.move()
+	s.Require().Contains(s.receive(conn).Objects, "548")
+}
+
+func (s *HubTestSuite) TestErrors() {
+	response, err := http.Get(s.server.URL + "/no_such_game")
+	s.Require().NoError(err)
+	s.Require().NoError(response.Body.Close())
+	s.Require().Equal(http.StatusNotFound, response.StatusCode)
+
+	conn := s.dial("spectator_game")
+	defer conn.Close()
+	s.receive(conn)
+
+	s.hub.Close()
+	_, _, err = conn.ReadMessage()
+	s.Require().True(websocket.IsCloseError(err, websocket.CloseGoingAway), err)
+
+	url := "ws" + strings.TrimPrefix(s.server.URL, "http") + "/spectator_game"
+	conn, _, err = websocket.DefaultDialer.Dial(url, nil)
+	s.Require().NoError(err)
+	defer conn.Close()
+	_, _, err = conn.ReadMessage()
+	s.Require().True(websocket.IsCloseError(err, websocket.CloseGoingAway), err)
+}
@@ -0,0 +1,9 @@
+package world
+
+import "fmt"
+
+var (
+	ErrOutOfBounds = fmt.Errorf("position out of the map")
+
+	ErrInvalidMap = fmt.Errorf("invalid map")
+)
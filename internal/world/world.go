@@ -0,0 +1,147 @@
+package world
+
+import (
+	"fmt"
+
+	"modules/internal/command"
+	"modules/internal/core"
+	"modules/internal/vector"
+)
+
+// Mode tells what happens to an object moving out of the map.
+type Mode string
+
+const (
+	// Clamp stops the object at the border.
+	Clamp Mode = "clamp"
+	// Reject fails the move with ErrOutOfBounds.
+	Reject Mode = "reject"
+	// Wrap brings the object in on the opposite side, the map is a torus.
+	Wrap Mode = "wrap"
+	// Destroy removes the object from the game.
+	Destroy Mode = "destroy"
+)
+
+// Map is the box from min, inclusive, to max, exclusive, objects move in.
+type Map struct {
+	min  vector.Vector
+	max  vector.Vector
+	mode Mode
+}
+
+func NewMap(min, max vector.Vector, mode Mode) (*Map, error) {
+	if len(min) == 0 || len(min) != len(max) {
+		return nil, fmt.Errorf("%w: corners %v and %v", ErrInvalidMap, min, max)
+	}
+
+	for i := range min {
+		if min[i] >= max[i] {
+			return nil, fmt.Errorf("%w: corners %v and %v", ErrInvalidMap, min, max)
+		}
+	}
+
+	switch mode {
+	case Clamp, Reject, Wrap, Destroy:
+	default:
+		return nil, fmt.Errorf("%w: unknown mode %q", ErrInvalidMap, mode)
+	}
+
+	return &Map{
+		min:  vector.New(min),
+		max:  vector.New(max),
+		mode: mode,
+	}, nil
+}
+
+func (m *Map) Mode() Mode {
+	return m.mode
+}
+
+func (m *Map) Contains(position vector.Vector) bool {
+	if len(position) != len(m.min) {
+		return false
+	}
+
+	for i, x := range position {
+		if x < m.min[i] || x >= m.max[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Place returns the position an object moving to the position ends up at,
+// which for the Reject and Destroy modes is the position itself.
+func (m *Map) Place(position vector.Vector) (vector.Vector, error) {
+	if len(position) != len(m.min) {
+		return nil, fmt.Errorf("%w: %d dimensions on a map of %d", ErrOutOfBounds, len(position), len(m.min))
+	}
+
+	switch m.mode {
+	case Clamp:
+		result := vector.New(position)
+		for i, x := range result {
+			result[i] = max(m.min[i], min(x, m.max[i]-1))
+		}
+		return result, nil
+	case Wrap:
+		result := vector.New(position)
+		for i, x := range result {
+			size := m.max[i] - m.min[i]
+			result[i] = m.min[i] + ((x-m.min[i])%size+size)%size
+		}
+		return result, nil
+	default:
+		return position, nil
+	}
+}
+
+// BoundedMovable keeps the object it decorates on the map, so a MoveCommand
+// moving it respects the map mode. In the Destroy mode the destroy command
+// is put into the queue when the object leaves the map, so that the commands
+// following the move still see the object.
+type BoundedMovable struct {
+	core.Movable
+	worldMap *Map
+	queue    core.Queue
+	destroy  core.Command
+}
+
+func NewBoundedMovable(worldMap *Map, movable core.Movable, queue core.Queue, destroy core.Command) *BoundedMovable {
+	return &BoundedMovable{
+		Movable:  movable,
+		worldMap: worldMap,
+		queue:    queue,
+		destroy:  destroy,
+	}
+}
+
+// Unwrap returns the decorated movable.
+func (b *BoundedMovable) Unwrap() core.Movable {
+	return b.Movable
+}
+
+func (b *BoundedMovable) SetPosition(position vector.Vector) error {
+	if b.worldMap.Contains(position) {
+		return b.Movable.SetPosition(position)
+	}
+
+	switch b.worldMap.mode {
+	case Reject:
+		return fmt.Errorf("%w: %v", ErrOutOfBounds, position)
+	case Destroy:
+		err := b.Movable.SetPosition(position)
+		if err != nil {
+			return err
+		}
+		command.PutBack(b.queue, b.destroy)
+		return nil
+	default:
+		placed, err := b.worldMap.Place(position)
+		if err != nil {
+			return err
+		}
+		return b.Movable.SetPosition(placed)
+	}
+}
@@ -0,0 +1,104 @@
+package world
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"modules/internal/command"
+	"modules/internal/core"
+	"modules/internal/mock"
+	"modules/internal/vector"
+)
+
+type sliceQueue struct {
+	commands []core.Command
+}
+
+func (q *sliceQueue) Put(command core.Command) {
+	q.commands = append(q.commands, command)
+}
+
+func (q *sliceQueue) TryPut(command core.Command) bool {
+	q.Put(command)
+	return true
+}
+
+func TestWorld(t *testing.T) {
+	suite.Run(t, new(WorldTestSuite))
+}
+
+type WorldTestSuite struct {
+	suite.Suite
+
+	queue   *sliceQueue
+	movable mock.MovableMock
+	destroy mock.CommandMock
+}
+
+func (s *WorldTestSuite) SetupTest() {
+	s.queue = &sliceQueue{}
+	s.movable = mock.MovableMock{}
+	s.movable.On("GetPosition").Return(vector.New([]int{8, 0, 5}), nil).
+		On("GetVelocity").Return(vector.New([]int{3, -2, 1}), nil)
+	s.destroy = mock.CommandMock{}
+}
+
+func (s *WorldTestSuite) move(mode Mode) error {
+	worldMap, err := NewMap(vector.New([]int{0, 0, 0}), vector.New([]int{10, 10, 10}), mode)
+	s.Require().NoError(err)
+	return command.NewMoveCommand(NewBoundedMovable(worldMap, &s.movable, s.queue, &s.destroy)).Execute()
+}
+
+func (s *WorldTestSuite) TestNewMap() {
+	_, err := NewMap(vector.New([]int{0, 0}), vector.New([]int{10}), Clamp)
+	s.Require().ErrorIs(err, ErrInvalidMap)
+	_, err = NewMap(vector.New([]int{0, 10}), vector.New([]int{10, 10}), Clamp)
+	s.Require().ErrorIs(err, ErrInvalidMap)
+	_, err = NewMap(vector.New([]int{0}), vector.New([]int{10}), "bounce")
+	s.Require().ErrorIs(err, ErrInvalidMap)
+}
+
+func (s *WorldTestSuite) TestInside() {
+	s.movable.On("GetVelocity").Unset()
+	s.movable.On("GetVelocity").Return(vector.New([]int{1, 2, 3}), nil).
+		On("SetPosition", vector.New([]int{9, 2, 8})).Return(nil)
+	s.Require().NoError(s.move(Reject))
+	s.movable.AssertExpectations(s.T())
+}
+
+func (s *WorldTestSuite) TestClamp() {
+	s.movable.On("SetPosition", vector.New([]int{9, 0, 6})).Return(nil)
+	s.Require().NoError(s.move(Clamp))
+	s.movable.AssertExpectations(s.T())
+}
+
+func (s *WorldTestSuite) TestReject() {
+	s.Require().ErrorIs(s.move(Reject), ErrOutOfBounds)
+	s.movable.AssertNotCalled(s.T(), "SetPosition", vector.New([]int{11, -2, 6}))
+}
+
+func (s *WorldTestSuite) TestWrap() {
+	s.movable.On("SetPosition", vector.New([]int{1, 8, 6})).Return(nil)
+	s.Require().NoError(s.move(Wrap))
+	s.movable.AssertExpectations(s.T())
+}
+
+func (s *WorldTestSuite) TestDestroy() {
+	s.movable.On("SetPosition", vector.New([]int{11, -2, 6})).Return(nil)
+	s.Require().NoError(s.move(Destroy))
+	s.movable.AssertExpectations(s.T())
+	s.Require().Equal([]core.Command{&s.destroy}, s.queue.commands)
+}
+
+func (s *WorldTestSuite) TestDimensions() {
+	worldMap, err := NewMap(vector.New([]int{0, 0}), vector.New([]int{10, 10}), Wrap)
+	s.Require().NoError(err)
+	s.Require().False(worldMap.Contains(vector.New([]int{1, 1, 1})))
+	_, err = worldMap.Place(vector.New([]int{1, 1, 1}))
+	s.Require().ErrorIs(err, ErrOutOfBounds)
+
+	position, err := worldMap.Place(vector.New([]int{-21, 35}))
+	s.Require().NoError(err)
+	s.Require().Equal(vector.New([]int{9, 5}), position)
+}